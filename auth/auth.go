@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+type claims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+type Issuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewIssuer(secret []byte, ttl time.Duration) *Issuer {
+	return &Issuer{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+func (i *Issuer) Issue(e model.Employee) (string, error) {
+
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Username: e.Username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   e.Id.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.ttl)),
+		},
+	})
+
+	return token.SignedString(i.secret)
+
+}
+
+func (i *Issuer) Parse(token string) (model.Employee, error) {
+
+	c := claims{}
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (any, error) {
+		return i.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return model.Employee{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	id, err := uuid.FromString(c.Subject)
+	if err != nil || len(c.Username) == 0 {
+		return model.Employee{}, ErrInvalidToken
+	}
+
+	return model.Employee{
+		Id:       id,
+		Username: c.Username,
+	}, nil
+
+}
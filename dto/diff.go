@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+)
+
+var diffIgnored = []string{"version", "updatedAt", "operation", "modifiedBy"}
+
+type Change struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+func fields(v any) (map[string]any, error) {
+
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]any{}
+	if err := json.Unmarshal(bytes, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+
+}
+
+func Diff[V any](from, to V) ([]Change, error) {
+
+	a, err := fields(from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := fields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	changes := []Change{}
+	for _, k := range keys {
+		if slices.Contains(diffIgnored, k) || reflect.DeepEqual(a[k], b[k]) {
+			continue
+		}
+		changes = append(changes, Change{Field: k, From: a[k], To: b[k]})
+	}
+
+	return changes, nil
+
+}
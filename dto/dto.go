@@ -0,0 +1,569 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+func timestamp(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func optionalTimestamp(t *time.Time) *string {
+
+	if t == nil {
+		return nil
+	}
+
+	res := timestamp(*t)
+
+	return &res
+
+}
+
+func List[T, V any](items []T, view func(T) V) []V {
+
+	res := make([]V, 0, len(items))
+	for _, item := range items {
+		res = append(res, view(item))
+	}
+
+	return res
+
+}
+
+type Page[V any] struct {
+	Items      []V     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Total      *int    `json:"total,omitempty"`
+}
+
+func NewPage[T model.Listable, V any](p model.Page[T], view func(T) V) Page[V] {
+
+	res := Page[V]{Items: List(p.Items, view), Total: p.Total}
+	if p.Next != nil {
+		next := p.Next.Encode()
+		res.NextCursor = &next
+	}
+
+	return res
+
+}
+
+type PublicTender struct {
+	Id             uuid.UUID               `json:"id"`
+	Name           string                  `json:"name"`
+	Description    string                  `json:"description"`
+	ServiceType    model.TenderServiceType `json:"serviceType"`
+	Status         model.TenderStatus      `json:"status"`
+	OrganizationId uuid.UUID               `json:"organizationId"`
+	Version        uint                    `json:"version"`
+	CreatedAt      string                  `json:"createdAt"`
+
+	SubmissionDeadline *string `json:"submissionDeadline,omitempty"`
+	DecisionDeadline   *string `json:"decisionDeadline,omitempty"`
+	CloseReason        *string `json:"closeReason,omitempty"`
+
+	BudgetMin    *model.Money       `json:"budgetMin,omitempty"`
+	BudgetMax    *model.Money       `json:"budgetMax,omitempty"`
+	Currency     *string            `json:"currency,omitempty"`
+	BudgetPolicy model.BudgetPolicy `json:"budgetPolicy"`
+
+	ApprovalRule      model.ApprovalRule  `json:"approvalRule"`
+	ApprovalThreshold *int                `json:"approvalThreshold,omitempty"`
+	RejectionRule     model.RejectionRule `json:"rejectionRule"`
+	MaxAwards         *int                `json:"maxAwards,omitempty"`
+}
+
+func NewPublicTender(t model.Tender) PublicTender {
+	return PublicTender{
+		Id:             t.Id,
+		Name:           t.Name,
+		Description:    t.Description,
+		ServiceType:    t.ServiceType,
+		Status:         t.Status,
+		OrganizationId: t.OrganizationId,
+		Version:        t.Version,
+		CreatedAt:      timestamp(t.CreatedAt),
+
+		SubmissionDeadline: optionalTimestamp(t.SubmissionDeadline),
+		DecisionDeadline:   optionalTimestamp(t.DecisionDeadline),
+		CloseReason:        t.CloseReason,
+
+		BudgetMin:    t.BudgetMin,
+		BudgetMax:    t.BudgetMax,
+		Currency:     t.Currency,
+		BudgetPolicy: t.BudgetPolicy,
+
+		ApprovalRule:      t.ApprovalRule,
+		ApprovalThreshold: t.ApprovalThreshold,
+		RejectionRule:     t.RejectionRule,
+		MaxAwards:         t.MaxAwards,
+	}
+}
+
+type TenderMatch struct {
+	PublicTender
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func NewTenderMatch(m model.TenderMatch) TenderMatch {
+	return TenderMatch{
+		PublicTender: NewPublicTender(m.Tender),
+		Rank:         m.Rank,
+		Snippet:      m.Snippet,
+	}
+}
+
+type OwnerTender struct {
+	PublicTender
+	UpdatedAt string `json:"updatedAt"`
+}
+
+func NewOwnerTender(t model.Tender) OwnerTender {
+	return OwnerTender{
+		PublicTender: NewPublicTender(t),
+		UpdatedAt:    timestamp(t.UpdatedAt),
+	}
+}
+
+type ReviewerBid struct {
+	Id          uuid.UUID           `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Status      model.BidStatus     `json:"status"`
+	TenderId    uuid.UUID           `json:"tenderId"`
+	AuthorType  model.BidAuthorType `json:"authorType"`
+	AuthorId    uuid.UUID           `json:"authorId"`
+	Version     int                 `json:"version"`
+	CreatedAt   string              `json:"createdAt"`
+
+	Price        *model.Money `json:"price,omitempty"`
+	Currency     *string      `json:"currency,omitempty"`
+	DeliveryDays *int         `json:"deliveryDays,omitempty"`
+	ValidUntil   *string      `json:"validUntil,omitempty"`
+	OutOfBudget  bool         `json:"outOfBudget"`
+}
+
+func NewReviewerBid(b model.Bid) ReviewerBid {
+	return ReviewerBid{
+		Id:          b.Id,
+		Name:        b.Name,
+		Description: b.Description,
+		Status:      b.Status,
+		TenderId:    b.TenderId,
+		AuthorType:  b.AuthorType,
+		AuthorId:    b.AuthorId,
+		Version:     b.Version,
+		CreatedAt:   timestamp(b.CreatedAt),
+
+		Price:        b.Price,
+		Currency:     b.Currency,
+		DeliveryDays: b.DeliveryDays,
+		ValidUntil:   optionalTimestamp(b.ValidUntil),
+		OutOfBudget:  b.OutOfBudget,
+	}
+}
+
+type OwnerBid struct {
+	ReviewerBid
+	UpdatedAt string `json:"updatedAt"`
+}
+
+func NewOwnerBid(b model.Bid) OwnerBid {
+	return OwnerBid{
+		ReviewerBid: NewReviewerBid(b),
+		UpdatedAt:   timestamp(b.UpdatedAt),
+	}
+}
+
+type BidReview struct {
+	Id          uuid.UUID `json:"id"`
+	Description string    `json:"description"`
+	CreatedAt   string    `json:"createdAt"`
+}
+
+func NewBidReview(f model.BidFeedback) BidReview {
+	return BidReview{
+		Id:          f.Id,
+		Description: f.Description,
+		CreatedAt:   timestamp(f.CreatedAt),
+	}
+}
+
+type BidMessage struct {
+	Id          uuid.UUID                `json:"id"`
+	BidId       uuid.UUID                `json:"bidId"`
+	ParentId    *uuid.UUID               `json:"parentId,omitempty"`
+	UserId      *uuid.UUID               `json:"userId,omitempty"`
+	Side        model.FeedbackSide       `json:"side"`
+	Visibility  model.FeedbackVisibility `json:"visibility"`
+	Description string                   `json:"description"`
+	Deleted     bool                     `json:"deleted"`
+	CreatedAt   string                   `json:"createdAt"`
+	UpdatedAt   *string                  `json:"updatedAt,omitempty"`
+}
+
+func NewBidMessage(f model.BidFeedback) BidMessage {
+
+	m := BidMessage{
+		Id:          f.Id,
+		BidId:       f.BidId,
+		ParentId:    f.ParentId,
+		UserId:      f.UserId,
+		Side:        f.Side,
+		Visibility:  f.Visibility,
+		Description: f.Description,
+		Deleted:     f.DeletedAt != nil,
+		CreatedAt:   timestamp(f.CreatedAt),
+	}
+	if f.UpdatedAt != nil {
+		updatedAt := timestamp(*f.UpdatedAt)
+		m.UpdatedAt = &updatedAt
+	}
+
+	return m
+
+}
+
+type BidRating struct {
+	Id                   uuid.UUID  `json:"id"`
+	BidId                uuid.UUID  `json:"bidId"`
+	TenderId             uuid.UUID  `json:"tenderId"`
+	AuthorId             uuid.UUID  `json:"authorId"`
+	AuthorOrganizationId *uuid.UUID `json:"authorOrganizationId,omitempty"`
+	OrganizationId       uuid.UUID  `json:"organizationId"`
+	Quality              int        `json:"quality"`
+	Timeliness           int        `json:"timeliness"`
+	Price                int        `json:"price"`
+	Comment              string     `json:"comment"`
+	CreatedAt            string     `json:"createdAt"`
+}
+
+func NewBidRating(r model.BidRating) BidRating {
+	return BidRating{
+		Id:                   r.Id,
+		BidId:                r.BidId,
+		TenderId:             r.TenderId,
+		AuthorId:             r.AuthorId,
+		AuthorOrganizationId: r.AuthorOrganizationId,
+		OrganizationId:       r.OrganizationId,
+		Quality:              r.Quality,
+		Timeliness:           r.Timeliness,
+		Price:                r.Price,
+		Comment:              r.Comment,
+		CreatedAt:            timestamp(r.CreatedAt),
+	}
+}
+
+type Reputation struct {
+	Ratings    int      `json:"ratings"`
+	Quality    *float64 `json:"quality"`
+	Timeliness *float64 `json:"timeliness"`
+	Price      *float64 `json:"price"`
+	Overall    *float64 `json:"overall"`
+}
+
+func NewReputation(r model.Reputation) Reputation {
+	return Reputation{
+		Ratings:    r.Ratings,
+		Quality:    r.Quality,
+		Timeliness: r.Timeliness,
+		Price:      r.Price,
+		Overall:    r.Overall,
+	}
+}
+
+type Notification struct {
+	Id             uuid.UUID         `json:"id"`
+	EventId        uuid.UUID         `json:"eventId"`
+	ActorId        *uuid.UUID        `json:"actorId,omitempty"`
+	OrganizationId uuid.UUID         `json:"organizationId"`
+	EntityType     model.EntityType  `json:"entityType"`
+	EntityId       uuid.UUID         `json:"entityId"`
+	Action         model.AuditAction `json:"action"`
+	Message        string            `json:"message"`
+	Read           bool              `json:"read"`
+	CreatedAt      string            `json:"createdAt"`
+}
+
+func NewNotification(n model.Notification) Notification {
+	return Notification{
+		Id:             n.Id,
+		EventId:        n.EventId,
+		ActorId:        n.ActorId,
+		OrganizationId: n.OrganizationId,
+		EntityType:     n.EntityType,
+		EntityId:       n.EntityId,
+		Action:         n.Action,
+		Message:        n.Message,
+		Read:           n.ReadAt != nil,
+		CreatedAt:      timestamp(n.CreatedAt),
+	}
+}
+
+type Subscription struct {
+	Channel model.NotificationChannel `json:"channel"`
+	Enabled bool                      `json:"enabled"`
+	Target  *string                   `json:"target,omitempty"`
+	Actions []model.AuditAction       `json:"actions"`
+}
+
+func NewSubscription(s model.Subscription) Subscription {
+	return Subscription{
+		Channel: s.Channel,
+		Enabled: s.Enabled,
+		Target:  s.Target,
+		Actions: s.Actions,
+	}
+}
+
+type TenderChange struct {
+	Id         uuid.UUID         `json:"id"`
+	TenderId   uuid.UUID         `json:"tenderId"`
+	Action     model.AuditAction `json:"action"`
+	EntityType model.EntityType  `json:"entityType"`
+	EntityId   uuid.UUID         `json:"entityId"`
+	Status     string            `json:"status,omitempty"`
+	MessageId  *uuid.UUID        `json:"messageId,omitempty"`
+	CreatedAt  string            `json:"createdAt"`
+}
+
+func NewTenderChange(c model.TenderChange) TenderChange {
+	return TenderChange{
+		Id:         c.Id,
+		TenderId:   c.TenderId,
+		Action:     c.Action,
+		EntityType: c.EntityType,
+		EntityId:   c.EntityId,
+		Status:     c.Status,
+		MessageId:  c.MessageId,
+		CreatedAt:  timestamp(c.CreatedAt),
+	}
+}
+
+type WebhookEvent struct {
+	Id        uuid.UUID              `json:"id"`
+	Type      model.WebhookEventType `json:"type"`
+	CreatedAt string                 `json:"createdAt"`
+	Data      any                    `json:"data"`
+}
+
+func NewWebhookEvent(e model.AuditEvent, t model.WebhookEventType) WebhookEvent {
+
+	event := WebhookEvent{
+		Id:        e.Id,
+		Type:      t,
+		CreatedAt: timestamp(e.CreatedAt),
+	}
+
+	switch subject := e.Subject.(type) {
+	case model.Tender:
+		event.Data = NewPublicTender(subject)
+	case model.Bid:
+		event.Data = NewReviewerBid(subject)
+	case model.BidFeedback:
+		event.Data = NewBidMessage(subject)
+	}
+
+	return event
+
+}
+
+type WebhookEndpoint struct {
+	Id         uuid.UUID                `json:"id"`
+	URL        string                   `json:"url"`
+	EventTypes []model.WebhookEventType `json:"eventTypes"`
+	Enabled    bool                     `json:"enabled"`
+	Secret     *string                  `json:"secret,omitempty"`
+	CreatedAt  string                   `json:"createdAt"`
+}
+
+func NewWebhookEndpoint(e model.WebhookEndpoint) WebhookEndpoint {
+	return WebhookEndpoint{
+		Id:         e.Id,
+		URL:        e.URL,
+		EventTypes: e.EventTypes,
+		Enabled:    e.Enabled == nil || *e.Enabled,
+		CreatedAt:  timestamp(e.CreatedAt),
+	}
+}
+
+type WebhookDelivery struct {
+	Id            uuid.UUID              `json:"id"`
+	WebhookId     uuid.UUID              `json:"webhookId"`
+	EventId       uuid.UUID              `json:"eventId"`
+	EventType     model.WebhookEventType `json:"eventType"`
+	Status        model.DeliveryStatus   `json:"status"`
+	Attempts      int                    `json:"attempts"`
+	NextAttemptAt *string                `json:"nextAttemptAt,omitempty"`
+	LastError     *string                `json:"lastError,omitempty"`
+	ResponseCode  *int                   `json:"responseCode,omitempty"`
+	Payload       json.RawMessage        `json:"payload"`
+	CreatedAt     string                 `json:"createdAt"`
+	DeliveredAt   *string                `json:"deliveredAt,omitempty"`
+}
+
+func NewWebhookDelivery(d model.WebhookDelivery) WebhookDelivery {
+
+	view := WebhookDelivery{
+		Id:           d.Id,
+		WebhookId:    d.EndpointId,
+		EventId:      d.EventId,
+		EventType:    d.EventType,
+		Status:       d.Status,
+		Attempts:     d.Attempts,
+		LastError:    d.LastError,
+		ResponseCode: d.ResponseCode,
+		Payload:      d.Payload,
+		CreatedAt:    timestamp(d.CreatedAt),
+	}
+	if d.Status == model.DeliveryStatusPending {
+		next := timestamp(d.NextAttemptAt)
+		view.NextAttemptAt = &next
+	}
+	if d.DeliveredAt != nil {
+		delivered := timestamp(*d.DeliveredAt)
+		view.DeliveredAt = &delivered
+	}
+
+	return view
+
+}
+
+type Error struct {
+	Reason string `json:"reason"`
+}
+
+type RoleAssignment struct {
+	OrganizationId uuid.UUID  `json:"organizationId"`
+	UserId         uuid.UUID  `json:"userId"`
+	Username       string     `json:"username"`
+	Role           model.Role `json:"role"`
+	CreatedAt      string     `json:"createdAt"`
+}
+
+func NewRoleAssignment(r model.RoleAssignment) RoleAssignment {
+	return RoleAssignment{
+		OrganizationId: r.OrganizationId,
+		UserId:         r.UserId,
+		Username:       r.Username,
+		Role:           r.Role,
+		CreatedAt:      timestamp(r.CreatedAt),
+	}
+}
+
+type TenderVersion struct {
+	OwnerTender
+	Operation  model.Operation `json:"operation"`
+	ModifiedBy *uuid.UUID      `json:"modifiedBy,omitempty"`
+}
+
+func NewTenderVersion(t model.Tender) TenderVersion {
+	return TenderVersion{
+		OwnerTender: NewOwnerTender(t),
+		Operation:   t.Operation,
+		ModifiedBy:  t.ModifiedBy,
+	}
+}
+
+type BidVersion struct {
+	OwnerBid
+	Operation  model.Operation `json:"operation"`
+	ModifiedBy *uuid.UUID      `json:"modifiedBy,omitempty"`
+}
+
+func NewBidVersion(b model.Bid) BidVersion {
+	return BidVersion{
+		OwnerBid:   NewOwnerBid(b),
+		Operation:  b.Operation,
+		ModifiedBy: b.ModifiedBy,
+	}
+}
+
+type AuditEvent struct {
+	Id             uuid.UUID         `json:"id"`
+	ActorId        *uuid.UUID        `json:"actorId"`
+	OrganizationId uuid.UUID         `json:"organizationId"`
+	EntityType     model.EntityType  `json:"entityType"`
+	EntityId       uuid.UUID         `json:"entityId"`
+	Action         model.AuditAction `json:"action"`
+	BeforeVersion  *int              `json:"beforeVersion"`
+	AfterVersion   *int              `json:"afterVersion"`
+	RequestId      *string           `json:"requestId"`
+	CreatedAt      string            `json:"createdAt"`
+}
+
+func NewAuditEvent(e model.AuditEvent) AuditEvent {
+	return AuditEvent{
+		Id:             e.Id,
+		ActorId:        e.ActorId,
+		OrganizationId: e.OrganizationId,
+		EntityType:     e.EntityType,
+		EntityId:       e.EntityId,
+		Action:         e.Action,
+		BeforeVersion:  e.BeforeVersion,
+		AfterVersion:   e.AfterVersion,
+		RequestId:      e.RequestId,
+		CreatedAt:      timestamp(e.CreatedAt),
+	}
+}
+
+type BidDecision struct {
+	UserId    uuid.UUID       `json:"userId"`
+	Username  string          `json:"username"`
+	Decision  model.BidStatus `json:"decision"`
+	CreatedAt string          `json:"createdAt"`
+}
+
+func NewBidDecision(d model.BidDecision) BidDecision {
+	return BidDecision{
+		UserId:    d.UserId,
+		Username:  d.Username,
+		Decision:  d.Decision,
+		CreatedAt: timestamp(d.CreatedAt),
+	}
+}
+
+type DecisionTally struct {
+	ApprovalRule      model.ApprovalRule  `json:"approvalRule"`
+	ApprovalThreshold *int                `json:"approvalThreshold,omitempty"`
+	RejectionRule     model.RejectionRule `json:"rejectionRule"`
+
+	Decisions []BidDecision `json:"decisions"`
+	Pending   []string      `json:"pending"`
+
+	Approvals          int `json:"approvals"`
+	Rejections         int `json:"rejections"`
+	RequiredApprovals  int `json:"requiredApprovals"`
+	RequiredRejections int `json:"requiredRejections"`
+	ApprovalsNeeded    int `json:"approvalsNeeded"`
+	RejectionsNeeded   int `json:"rejectionsNeeded"`
+}
+
+func NewDecisionTally(t model.DecisionTally) DecisionTally {
+
+	pending := make([]string, 0, len(t.Pending))
+	for _, e := range t.Pending {
+		pending = append(pending, e.Username)
+	}
+
+	return DecisionTally{
+		ApprovalRule:       t.Tender.ApprovalRule,
+		ApprovalThreshold:  t.Tender.ApprovalThreshold,
+		RejectionRule:      t.Tender.RejectionRule,
+		Decisions:          List(t.Decisions, NewBidDecision),
+		Pending:            pending,
+		Approvals:          t.Approvals,
+		Rejections:         t.Rejections,
+		RequiredApprovals:  t.RequiredApprovals,
+		RequiredRejections: t.RequiredRejections,
+		ApprovalsNeeded:    max(0, t.RequiredApprovals-t.Approvals),
+		RejectionsNeeded:   max(0, t.RequiredRejections-t.Rejections),
+	}
+
+}
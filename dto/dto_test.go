@@ -0,0 +1,363 @@
+package dto_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"zadanie/dto"
+	"zadanie/model"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofrs/uuid"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+var (
+	created  = time.Date(2024, 9, 1, 10, 30, 0, 0, time.UTC)
+	updated  = time.Date(2024, 9, 2, 8, 0, 0, 0, time.UTC)
+	deadline = time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	orgId       = uuid.Must(uuid.FromString("550e8400-e29b-41d4-a716-446655440001"))
+	userId      = uuid.Must(uuid.FromString("550e8400-e29b-41d4-a716-446655440000"))
+	authorId    = uuid.Must(uuid.FromString("550e8400-e29b-41d4-a716-446655440003"))
+	authorOrgId = uuid.Must(uuid.FromString("550e8400-e29b-41d4-a716-446655440004"))
+	tenderId    = uuid.Must(uuid.FromString("7c9e6679-7425-40de-944b-e07fc1f90ae7"))
+	bidId       = uuid.Must(uuid.FromString("9b2f3c1e-8d4a-4f6b-a1c2-3d4e5f6a7b8c"))
+	messageId   = uuid.Must(uuid.FromString("1f0e2d3c-4b5a-4978-8695-a4b3c2d1e0f9"))
+	eventId     = uuid.Must(uuid.FromString("2a3b4c5d-6e7f-4801-9a2b-3c4d5e6f7a8b"))
+	webhookId   = uuid.Must(uuid.FromString("3b4c5d6e-7f80-4912-ab3c-4d5e6f7a8b9c"))
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func fullTender() model.Tender {
+	return model.Tender{
+		Id:                 tenderId,
+		Name:               "Доставка \"срочно\" <b>",
+		Description:        "Доставка стройматериалов",
+		ServiceType:        model.TenderServiceTypeDelivery,
+		Status:             model.TenderStatusClosed,
+		OrganizationId:     orgId,
+		Version:            4,
+		CreatedAt:          created,
+		UpdatedAt:          updated,
+		SubmissionDeadline: ptr(deadline.Add(-24 * time.Hour)),
+		DecisionDeadline:   ptr(deadline),
+		CloseReason:        ptr(model.TenderCloseReasonAwarded),
+		BudgetMin:          ptr(model.Money(10050)),
+		BudgetMax:          ptr(model.Money(100000)),
+		Currency:           ptr("RUB"),
+		BudgetPolicy:       model.BudgetPolicyReject,
+		ApprovalRule:       model.ApprovalRuleFixed,
+		ApprovalThreshold:  ptr(2),
+		RejectionRule:      model.RejectionRuleMajority,
+		MaxAwards:          ptr(2),
+		ModifiedBy:         &userId,
+		Operation:          model.OperationDecision,
+	}
+}
+
+func draftTender() model.Tender {
+	return model.Tender{
+		Id:             tenderId,
+		Name:           "t",
+		Description:    "d",
+		ServiceType:    model.TenderServiceTypeConstruction,
+		Status:         model.TenderStatusCreated,
+		OrganizationId: orgId,
+		Version:        1,
+		CreatedAt:      created,
+		UpdatedAt:      created,
+		BudgetPolicy:   model.BudgetPolicyWarn,
+		ApprovalRule:   model.ApprovalRuleQuorum,
+		RejectionRule:  model.RejectionRuleFirstVeto,
+		Operation:      model.OperationCreate,
+	}
+}
+
+func fullBid() model.Bid {
+	return model.Bid{
+		Id:           bidId,
+		Name:         "b",
+		Description:  "Привезём за 3 дня",
+		Status:       model.BidStatusApproved,
+		TenderId:     tenderId,
+		AuthorType:   model.BidAuthorTypeOrganization,
+		AuthorId:     authorId,
+		Version:      3,
+		CreatedAt:    created,
+		UpdatedAt:    updated,
+		Price:        ptr(model.Money(50025)),
+		Currency:     ptr("RUB"),
+		DeliveryDays: ptr(3),
+		ValidUntil:   ptr(deadline),
+		OutOfBudget:  true,
+		ModifiedBy:   &userId,
+		Operation:    model.OperationDecision,
+	}
+}
+
+func draftBid() model.Bid {
+	return model.Bid{
+		Id:          bidId,
+		Name:        "b",
+		Description: "d",
+		Status:      model.BidStatusCreated,
+		TenderId:    tenderId,
+		AuthorType:  model.BidAuthorTypeUser,
+		AuthorId:    authorId,
+		Version:     1,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+		Operation:   model.OperationCreate,
+	}
+}
+
+func message() model.BidFeedback {
+	return model.BidFeedback{
+		Id:          messageId,
+		TenderId:    tenderId,
+		BidId:       bidId,
+		UserId:      &userId,
+		Description: "Уточните сроки; --",
+		CreatedAt:   created,
+		ParentId:    &eventId,
+		Side:        model.FeedbackSideReviewer,
+		Visibility:  model.FeedbackVisibilityPublic,
+		UpdatedAt:   &updated,
+	}
+}
+
+func event() model.AuditEvent {
+	return model.AuditEvent{
+		Id:             eventId,
+		ActorId:        &userId,
+		OrganizationId: orgId,
+		EntityType:     model.EntityTender,
+		EntityId:       tenderId,
+		Action:         model.AuditTenderClose,
+		BeforeVersion:  ptr(3),
+		AfterVersion:   ptr(4),
+		RequestId:      ptr("host/abc-000001"),
+		CreatedAt:      updated,
+		Subject:        fullTender(),
+	}
+}
+
+func TestGolden(t *testing.T) {
+
+	doc, err := openapi3.NewLoader().LoadFromFile(filepath.Join("..", "api", "openapi.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openapi3.SchemaErrorDetailsDisabled = true
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForUUIDOfRFC4122))
+
+	changes, err := dto.Diff(dto.NewOwnerTender(draftTender()), dto.NewOwnerTender(fullTender()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tally := model.DecisionTally{
+		Tender: fullTender(),
+		Decisions: []model.BidDecision{
+			{BidId: bidId, UserId: userId, Username: "user1", Decision: model.BidStatusApproved, CreatedAt: updated},
+		},
+		Pending:            []model.Employee{{Id: authorId, Username: "user2"}},
+		Approvals:          1,
+		RequiredApprovals:  2,
+		RequiredRejections: 2,
+	}
+
+	payload, err := json.Marshal(dto.NewWebhookEvent(event(), model.WebhookTenderClosed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := model.Page[model.Tender]{
+		Items: []model.Tender{fullTender()},
+		Next:  &model.Cursor{Sort: "-name", Key: "t", Id: tenderId},
+		Total: ptr(3),
+	}
+
+	tests := []struct {
+		name   string
+		schema string
+		value  any
+	}{
+		{"tender", "tender", dto.NewPublicTender(fullTender())},
+		{"tender_draft", "tender", dto.NewOwnerTender(draftTender())},
+		{"tender_owner", "tender", dto.NewOwnerTender(fullTender())},
+		{"tender_version", "tenderSnapshot", dto.NewTenderVersion(fullTender())},
+		{"tender_match", "tenderMatch", dto.NewTenderMatch(model.TenderMatch{Tender: fullTender(), Rank: 0.25, Snippet: "<b>Доставка</b>"})},
+		{"bid", "bid", dto.NewReviewerBid(fullBid())},
+		{"bid_draft", "bid", dto.NewOwnerBid(draftBid())},
+		{"bid_owner", "bid", dto.NewOwnerBid(fullBid())},
+		{"bid_version", "bidSnapshot", dto.NewBidVersion(fullBid())},
+		{"bid_review", "bidReview", dto.NewBidReview(message())},
+		{"bid_message", "bidMessage", dto.NewBidMessage(message())},
+		{"bid_message_deleted", "bidMessage", dto.NewBidMessage(model.BidFeedback{
+			Id: messageId, BidId: bidId, Side: model.FeedbackSideSystem, Visibility: model.FeedbackVisibilityInternal,
+			CreatedAt: created, DeletedAt: &updated,
+		})},
+		{"bid_rating", "bidRating", dto.NewBidRating(model.BidRating{
+			Id: messageId, BidId: bidId, TenderId: tenderId, AuthorId: authorId, AuthorOrganizationId: &authorOrgId,
+			UserId: &userId, OrganizationId: orgId, Quality: 5, Timeliness: 4, Price: 3, Comment: "ok", CreatedAt: updated,
+		})},
+		{"bid_decision", "bidDecisionEntry", dto.NewBidDecision(tally.Decisions[0])},
+		{"decision_tally", "decisionTally", dto.NewDecisionTally(tally)},
+		{"change", "change", changes[0]},
+		{"reputation", "reputation", dto.NewReputation(model.Reputation{Ratings: 2, Quality: ptr(4.5), Timeliness: ptr(4.0), Price: ptr(3.5), Overall: ptr(4.0)})},
+		{"reputation_empty", "reputation", dto.NewReputation(model.Reputation{})},
+		{"notification", "notification", dto.NewNotification(model.Notification{
+			Id: messageId, UserId: authorId, EventId: eventId, ActorId: &userId, OrganizationId: orgId,
+			EntityType: model.EntityBid, EntityId: bidId, Action: model.AuditBidApprove, Message: "bid approved",
+			CreatedAt: updated, ReadAt: &updated,
+		})},
+		{"subscription", "subscription", dto.NewSubscription(model.Subscription{
+			UserId: authorId, Channel: model.NotificationChannelEmail, Enabled: true, Target: ptr("a@b.c"),
+			Actions: []model.AuditAction{model.AuditBidApprove}, UpdatedAt: &updated,
+		})},
+		{"tender_change", "tenderChange", dto.NewTenderChange(model.TenderChange{
+			Id: eventId, TenderId: tenderId, Action: model.AuditBidApprove, EntityType: model.EntityBid, EntityId: bidId,
+			Status: string(model.BidStatusApproved), CreatedAt: updated, TenderOrganizationId: orgId, BidOrganizationId: &authorOrgId,
+		})},
+		{"webhook_event", "webhookEvent", dto.NewWebhookEvent(event(), model.WebhookTenderClosed)},
+		{"webhook_endpoint", "webhookEndpoint", dto.NewWebhookEndpoint(model.WebhookEndpoint{
+			Id: webhookId, OrganizationId: orgId, URL: "https://example.com/hook", Secret: "s",
+			EventTypes: []model.WebhookEventType{model.WebhookTenderClosed}, CreatedBy: &userId, CreatedAt: created,
+		})},
+		{"webhook_delivery", "webhookDelivery", dto.NewWebhookDelivery(model.WebhookDelivery{
+			Id: messageId, EndpointId: webhookId, OrganizationId: orgId, EventId: eventId, EventType: model.WebhookTenderClosed,
+			Payload: payload, Status: model.DeliveryStatusPending, Attempts: 2,
+			NextAttemptAt: deadline, LastError: ptr("status 503"), ResponseCode: ptr(503), CreatedAt: created,
+			URL: "https://example.com/hook", Secret: "s",
+		})},
+		{"role_assignment", "roleAssignment", dto.NewRoleAssignment(model.RoleAssignment{
+			OrganizationId: orgId, UserId: userId, Username: "user1", Role: model.RoleOwner, CreatedAt: created,
+		})},
+		{"audit_event", "auditEvent", dto.NewAuditEvent(event())},
+		{"audit_event_system", "auditEvent", dto.NewAuditEvent(model.AuditEvent{
+			Id: eventId, OrganizationId: orgId, EntityType: model.EntityTender, EntityId: tenderId,
+			Action: model.AuditTenderClose, CreatedAt: updated,
+		})},
+		{"page", "page", dto.NewPage(page, dto.NewPublicTender)},
+		{"error", "errorResponse", dto.Error{Reason: "tender wasn't found"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := json.MarshalIndent(tt.value, "", "\t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", tt.name+".json")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s differs from golden file:\n%s", tt.name, got)
+			}
+
+			ref, ok := doc.Components.Schemas[tt.schema]
+			if !ok {
+				t.Fatalf("schema %q is not in the spec", tt.schema)
+			}
+
+			var v any
+			if err := json.Unmarshal(got, &v); err != nil {
+				t.Fatal(err)
+			}
+			if err := ref.Value.VisitJSON(v, openapi3.VisitAsResponse(), openapi3.EnableFormatValidation(), openapi3.MultiErrors()); err != nil {
+				t.Fatalf("does not match %s: %v", tt.schema, err)
+			}
+			if key, ok := undeclared(ref.Value, v, tt.schema); ok {
+				t.Fatalf("%s is not declared in the spec", key)
+			}
+
+		})
+	}
+
+}
+
+// undeclared returns the first key of v that the schema does not describe.
+func undeclared(schema *openapi3.Schema, v any, path string) (string, bool) {
+
+	if len(schema.OneOf) != 0 {
+		for _, alt := range schema.OneOf {
+			if alt.Value.VisitJSON(v, openapi3.VisitAsResponse()) == nil {
+				return undeclared(alt.Value, v, path)
+			}
+		}
+		return "", false
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		props := properties(schema)
+		if len(props) == 0 {
+			return "", false
+		}
+		for k, field := range v {
+			prop, ok := props[k]
+			if !ok {
+				return path + "." + k, true
+			}
+			if key, ok := undeclared(prop, field, path+"."+k); ok {
+				return key, true
+			}
+		}
+	case []any:
+		items := schema.Items
+		for _, s := range schema.AllOf {
+			if s.Value.Items != nil {
+				items = s.Value.Items
+			}
+		}
+		if items == nil {
+			return "", false
+		}
+		for _, item := range v {
+			if key, ok := undeclared(items.Value, item, path+"[]"); ok {
+				return key, true
+			}
+		}
+	}
+
+	return "", false
+
+}
+
+func properties(schema *openapi3.Schema) map[string]*openapi3.Schema {
+
+	props := map[string]*openapi3.Schema{}
+	for k, p := range schema.Properties {
+		props[k] = p.Value
+	}
+	for _, s := range schema.AllOf {
+		for k, p := range properties(s.Value) {
+			props[k] = p
+		}
+	}
+
+	return props
+
+}
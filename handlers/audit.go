@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"zadanie/dto"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+func Audit(s Storage) http.HandlerFunc {
+	method := "audit"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		query := r.URL.Query()
+		filter := model.AuditFilter{
+			EntityType: model.EntityType(query.Get("entity_type")),
+			Actor:      query.Get("actor"),
+		}
+
+		if len(filter.EntityType) != 0 && !filter.EntityType.Validate() {
+			writeErrorResponse(w, ErrIncorrectEntityType, 400, method)
+			return
+		}
+
+		if tmp := query.Get("entity_id"); len(tmp) != 0 {
+			entityId, err := uuid.FromString(tmp)
+			if err != nil {
+				writeErrorResponse(w, err, 400, method)
+				return
+			}
+			filter.EntityId = &entityId
+		}
+
+		opts, err := listOptions(r, "-"+model.SortCreatedAt, model.AuditSorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, query.Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		page, err := s.ReadAudit(r.Context(), username, filter, opts)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeList(w, r, page, dto.NewAuditEvent, method)
+
+	}
+}
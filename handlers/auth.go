@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type TokenParser interface {
+	Parse(token string) (model.Employee, error)
+}
+
+type authKey struct{}
+
+type authState struct {
+	employee *model.Employee
+	compat   bool
+}
+
+func Authenticate(p TokenParser, compat bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			state := authState{compat: compat}
+
+			if header := r.Header.Get("Authorization"); len(header) != 0 {
+
+				token, ok := strings.CutPrefix(header, "Bearer ")
+				if !ok || p == nil {
+					writeErrorResponse(w, ErrInvalidAuthorization, 401, "authenticate")
+					return
+				}
+
+				employee, err := p.Parse(token)
+				if err != nil {
+					writeErrorResponse(w, err, 401, "authenticate")
+					return
+				}
+
+				state.employee = &employee
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authKey{}, state)))
+
+		})
+	}
+}
+
+func callerUsername(r *http.Request, compat string) (string, error) {
+
+	state, _ := r.Context().Value(authKey{}).(authState)
+
+	switch {
+	case state.employee != nil:
+		return state.employee.Username, nil
+	case !state.compat:
+		return "", ErrUnauthorized
+	case len(compat) != 0:
+		return compat, nil
+	default:
+		return "", ErrPassUsername
+	}
+
+}
+
+func checkAuthor(r *http.Request, authorId uuid.UUID) error {
+
+	state, _ := r.Context().Value(authKey{}).(authState)
+
+	switch {
+	case state.employee != nil && state.employee.Id != authorId:
+		return ErrForeignAuthor
+	case state.employee != nil, state.compat:
+		return nil
+	default:
+		return ErrUnauthorized
+	}
+
+}
@@ -6,3 +6,30 @@ var ErrPassUsername = errors.New("pass username")
 var ErrPassFeedback = errors.New("pass feedback")
 var ErrIncorrectStatus = errors.New("incorrect status")
 var ErrNothingToDo = errors.New("nothing to do")
+var ErrInvalidRequest = errors.New("request doesn't match the specification")
+var ErrIncorrectServiceType = errors.New("incorrect service type")
+var ErrEmptyName = errors.New("name cannot be empty")
+var ErrUnauthorized = errors.New("authentication required")
+var ErrInvalidAuthorization = errors.New("authorization header must carry a bearer token")
+var ErrPassAuthor = errors.New("pass author username")
+var ErrForeignAuthor = errors.New("bid author must be the authenticated employee")
+var ErrIncorrectRole = errors.New("incorrect role")
+var ErrIncorrectBudgetPolicy = errors.New("incorrect budget policy")
+var ErrIncorrectSort = errors.New("incorrect sort")
+var ErrPassQuery = errors.New("pass search query")
+var ErrIncorrectCursor = errors.New("incorrect cursor")
+var ErrPassVersions = errors.New("pass from and to versions")
+var ErrIncorrectEntityType = errors.New("incorrect entity type")
+var ErrIncorrectDecisionPolicy = errors.New("incorrect decision policy")
+var ErrIncorrectVisibility = errors.New("incorrect visibility")
+var ErrIncorrectScore = errors.New("scores must be between 1 and 5")
+var ErrPassReputationSubject = errors.New("pass either authorUsername or organizationId")
+var ErrIncorrectSubscription = errors.New("incorrect subscription")
+var ErrIncorrectWebhook = errors.New("webhook needs a public http(s) url and known event types")
+var ErrIncorrectDeliveryStatus = errors.New("incorrect delivery status")
+var ErrIncorrectMaxAwards = errors.New("maxAwards must be positive")
+var ErrStreamingUnsupported = errors.New("streaming is not supported")
+var ErrIncorrectPagination = errors.New("limit and offset must be non-negative integers")
+var ErrCursorWithOffset = errors.New("offset cannot be combined with cursor")
+var ErrRouteNotFound = errors.New("route not found")
+var ErrMethodNotAllowed = errors.New("method not allowed")
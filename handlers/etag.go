@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"zadanie/model"
+)
+
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+func ifMatch(r *http.Request) model.Versions {
+
+	values := r.Header.Values("if-match")
+	if len(values) == 0 {
+		return nil
+	}
+
+	versions := model.Versions{}
+	for _, tag := range strings.Split(strings.Join(values, ","), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil
+		}
+
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+
+		if v, err := strconv.Atoi(tag[1 : len(tag)-1]); err == nil {
+			versions = append(versions, v)
+		}
+	}
+
+	return versions
+
+}
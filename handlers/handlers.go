@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"zadanie/dto"
 	"zadanie/model"
 	"zadanie/storage"
 
@@ -16,24 +17,40 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+const outOfBudgetWarning = `199 - "price is outside the tender budget"`
+
 func writeErrorResponse(w http.ResponseWriter, err error, statusCode int, method string) (int, error) {
-	defer log.Println("%s: ", method, err.Error())
+	defer log.Printf("%s: %s", method, err.Error())
+
+	bytes, _ := json.Marshal(dto.Error{Reason: err.Error()})
 
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(statusCode)
-	return w.Write([]byte(fmt.Sprintf(`{"reason":%q}`, err.Error())))
+	return w.Write(bytes)
 }
 
 func errStatusCode(err error) (code int) {
 	switch {
-	case errors.Is(err, storage.ErrIncorrectUser), errors.Is(err, ErrPassUsername):
+	case errors.Is(err, storage.ErrIncorrectUser), errors.Is(err, ErrPassUsername), errors.Is(err, ErrUnauthorized):
 		code = 401
-	case errors.Is(err, storage.ErrNotEnoughPerm):
+	case errors.Is(err, storage.ErrNotEnoughPerm), errors.Is(err, ErrForeignAuthor):
 		code = 403
 	case errors.Is(err, storage.ErrTenderNotFound),
 		errors.Is(err, storage.ErrBidNotFound),
-		errors.Is(err, storage.ErrVersionNotFound):
+		errors.Is(err, storage.ErrVersionNotFound),
+		errors.Is(err, storage.ErrRoleNotFound),
+		errors.Is(err, storage.ErrMessageNotFound),
+		errors.Is(err, storage.ErrNotificationNotFound),
+		errors.Is(err, storage.ErrWebhookNotFound),
+		errors.Is(err, storage.ErrDeliveryNotFound):
 		code = 404
+	case errors.Is(err, storage.ErrBidAwarded), errors.Is(err, storage.ErrDecisionSubmitted), errors.Is(err, storage.ErrMessageLocked),
+		errors.Is(err, storage.ErrBidNotRatable), errors.Is(err, storage.ErrBidRated):
+		code = 409
+	case errors.Is(err, storage.ErrVersionMismatch):
+		code = 412
+	case errors.Is(err, context.DeadlineExceeded):
+		code = 504
 	default:
 		code = 400
 	}
@@ -41,10 +58,10 @@ func errStatusCode(err error) (code int) {
 	return
 }
 
-func Ping(ctx context.Context, s Storage) http.HandlerFunc {
+func Ping(s Storage) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		if err := s.Ping(ctx); err != nil {
+		if err := s.Ping(r.Context()); err != nil {
 			writeErrorResponse(w, err, 500, "ping")
 			return
 		}
@@ -54,19 +71,15 @@ func Ping(ctx context.Context, s Storage) http.HandlerFunc {
 	}
 }
 
-func Tenders(ctx context.Context, s Storage) http.HandlerFunc {
+func Tenders(s Storage) http.HandlerFunc {
 	method := "tenders"
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		limit := 5
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
-			limit = tmp
-		}
-
-		offset := 0
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
-			offset = tmp
+		opts, err := listOptions(r, model.SortName, model.TenderSorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
 		}
 
 		serviceTypes := []model.TenderServiceType{}
@@ -74,30 +87,63 @@ func Tenders(ctx context.Context, s Storage) http.HandlerFunc {
 		for _, t := range r.URL.Query()["service_type"] {
 			tst := model.TenderServiceType(t)
 			if !tst.Validate() {
-				continue
+				writeErrorResponse(w, ErrIncorrectServiceType, 400, method)
+				return
 			}
 			serviceTypes = append(serviceTypes, tst)
 		}
 
-		tenders, err := s.ReadTenders(ctx, limit, offset, serviceTypes)
+		tenders, err := s.ReadTenders(r.Context(), opts, serviceTypes)
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
-		bytes, err := json.Marshal(tenders)
+		writeList(w, r, tenders, dto.NewPublicTender, method)
+
+	}
+}
+
+func SearchTenders(s Storage) http.HandlerFunc {
+	method := "search tenders"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		search := strings.TrimSpace(r.URL.Query().Get("q"))
+		if len(search) == 0 {
+			writeErrorResponse(w, ErrPassQuery, 400, method)
+			return
+		}
+
+		opts, err := listOptions(r, model.SortRank, model.SearchSorts)
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
-		w.Header().Set("content-type", "application/json")
-		w.Write(bytes)
+		serviceTypes := []model.TenderServiceType{}
+
+		for _, t := range r.URL.Query()["service_type"] {
+			tst := model.TenderServiceType(t)
+			if !tst.Validate() {
+				writeErrorResponse(w, ErrIncorrectServiceType, 400, method)
+				return
+			}
+			serviceTypes = append(serviceTypes, tst)
+		}
+
+		matches, err := s.SearchTenders(r.Context(), search, opts, serviceTypes)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeList(w, r, matches, dto.NewTenderMatch, method)
 
 	}
 }
 
-func NewTender(ctx context.Context, s Storage) http.HandlerFunc {
+func NewTender(s Storage) http.HandlerFunc {
 	method := "new tender"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -115,64 +161,80 @@ func NewTender(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		tenders, err := s.CreateTender(ctx, t, t.CreatorUsername)
+		if len(t.Name) == 0 {
+			writeErrorResponse(w, ErrEmptyName, 400, method)
+			return
+		}
+
+		if len(t.BudgetPolicy) != 0 && !t.BudgetPolicy.Validate() {
+			writeErrorResponse(w, ErrIncorrectBudgetPolicy, 400, method)
+			return
+		}
+
+		if !t.ValidatePolicy() {
+			writeErrorResponse(w, ErrIncorrectDecisionPolicy, 400, method)
+			return
+		}
+
+		if t.MaxAwards != nil && *t.MaxAwards < 1 {
+			writeErrorResponse(w, ErrIncorrectMaxAwards, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, t.CreatorUsername)
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		tenders, err := s.CreateTender(r.Context(), t, username)
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err = json.Marshal(tenders)
+		bytes, err = json.Marshal(dto.NewOwnerTender(tenders))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(int(tenders.Version)))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func MyTenders(ctx context.Context, s Storage) http.HandlerFunc {
+func MyTenders(s Storage) http.HandlerFunc {
 	method := "my tenders"
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		limit := 5
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
-			limit = tmp
-		}
-
-		offset := 0
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
-			offset = tmp
-		}
-
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		opts, err := listOptions(r, model.SortName, model.TenderSorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
-		tenders, err := s.ReadMyTenders(ctx, username, limit, offset)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
 		if err != nil {
-			writeErrorResponse(w, err, errStatusCode(err), method)
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		bytes, err := json.Marshal(tenders)
+		tenders, err := s.ReadMyTenders(r.Context(), username, opts)
 		if err != nil {
-			writeErrorResponse(w, err, 400, method)
+			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		w.Header().Set("content-type", "application/json")
-		w.Write(bytes)
+		writeList(w, r, tenders, dto.NewOwnerTender, method)
 
 	}
 }
 
-func TenderStatus(ctx context.Context, s Storage) http.HandlerFunc {
+func TenderStatus(s Storage) http.HandlerFunc {
 	method := "tender status"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -183,31 +245,32 @@ func TenderStatus(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		tender, err := s.ReadTenderStatus(ctx, tenderId, username)
+		tender, err := s.ReadTender(r.Context(), tenderId, username)
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(tender)
+		bytes, err := json.Marshal(tender.Status)
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(int(tender.Version)))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func UpdateTenderStatus(ctx context.Context, s Storage) http.HandlerFunc {
+func UpdateTenderStatus(s Storage) http.HandlerFunc {
 	method := "update tender status"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -224,31 +287,32 @@ func UpdateTenderStatus(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		tender, err := s.UpdateTenderStatus(ctx, tenderId, username, status)
+		tender, err := s.UpdateTenderStatus(r.Context(), tenderId, username, status, ifMatch(r))
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(tender)
+		bytes, err := json.Marshal(dto.NewOwnerTender(tender))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(int(tender.Version)))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func EditTender(ctx context.Context, s Storage) http.HandlerFunc {
+func EditTender(s Storage) http.HandlerFunc {
 	method := "edit tender"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -259,9 +323,9 @@ func EditTender(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
@@ -278,30 +342,38 @@ func EditTender(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		if len(t.Name) == 0 && len(t.Description) == 0 && len(t.ServiceType) == 0 {
+		if len(t.Name) == 0 && len(t.Description) == 0 && len(t.ServiceType) == 0 &&
+			t.SubmissionDeadline == nil && t.DecisionDeadline == nil &&
+			t.BudgetMin == nil && t.BudgetMax == nil && t.Currency == nil && len(t.BudgetPolicy) == 0 {
 			writeErrorResponse(w, ErrNothingToDo, 400, method)
 			return
 		}
 
-		tender, err := s.UpdateTender(ctx, tenderId, username, t)
+		if len(t.BudgetPolicy) != 0 && !t.BudgetPolicy.Validate() {
+			writeErrorResponse(w, ErrIncorrectBudgetPolicy, 400, method)
+			return
+		}
+
+		tender, err := s.UpdateTender(r.Context(), tenderId, username, t, ifMatch(r))
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err = json.Marshal(tender)
+		bytes, err = json.Marshal(dto.NewOwnerTender(tender))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(int(tender.Version)))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 
 }
-func RollbackTender(ctx context.Context, s Storage) http.HandlerFunc {
+func RollbackTender(s Storage) http.HandlerFunc {
 	method := "rollback tender version"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -318,30 +390,31 @@ func RollbackTender(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		tender, err := s.RollbackTender(ctx, tenderId, username, version)
+		tender, err := s.RollbackTender(r.Context(), tenderId, username, version, ifMatch(r))
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(tender)
+		bytes, err := json.Marshal(dto.NewOwnerTender(tender))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(int(tender.Version)))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
-func NewBid(ctx context.Context, s Storage) http.HandlerFunc {
+func NewBid(s Storage) http.HandlerFunc {
 	method := "new bid"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -359,64 +432,67 @@ func NewBid(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		bid, err := s.CreateBid(ctx, b)
+		if len(b.Name) == 0 {
+			writeErrorResponse(w, ErrEmptyName, 400, method)
+			return
+		}
+
+		if err := checkAuthor(r, b.AuthorId); err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bid, err := s.CreateBid(r.Context(), b)
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err = json.Marshal(bid)
+		bytes, err = json.Marshal(dto.NewOwnerBid(bid))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(bid.Version))
+		if bid.OutOfBudget {
+			w.Header().Set("warning", outOfBudgetWarning)
+		}
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func MyBids(ctx context.Context, s Storage) http.HandlerFunc {
+func MyBids(s Storage) http.HandlerFunc {
 	method := "my bids"
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		limit := 5
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
-			limit = tmp
-		}
-
-		offset := 0
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
-			offset = tmp
-		}
-
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		opts, err := listOptions(r, model.SortName, model.BidSorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
-		bids, err := s.ReadMyBids(ctx, username, limit, offset)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
 		if err != nil {
-			writeErrorResponse(w, err, errStatusCode(err), method)
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		bytes, err := json.Marshal(bids)
+		bids, err := s.ReadMyBids(r.Context(), username, opts)
 		if err != nil {
-			writeErrorResponse(w, err, 400, method)
+			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		w.Header().Set("content-type", "application/json")
-		w.Write(bytes)
+		writeList(w, r, bids, dto.NewOwnerBid, method)
 
 	}
 }
 
-func BidsList(ctx context.Context, s Storage) http.HandlerFunc {
+func BidsList(s Storage) http.HandlerFunc {
 	method := "bids list"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -427,41 +503,30 @@ func BidsList(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		limit := 5
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
-			limit = tmp
-		}
-
-		offset := 0
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
-			offset = tmp
-		}
-
-		bids, err := s.ReadBids(ctx, tenderId, username, limit, offset)
+		opts, err := listOptions(r, model.SortName, model.BidSorts)
 		if err != nil {
-			writeErrorResponse(w, err, errStatusCode(err), method)
+			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
-		bytes, err := json.Marshal(bids)
+		bids, err := s.ReadBids(r.Context(), tenderId, username, opts)
 		if err != nil {
-			writeErrorResponse(w, err, 400, method)
+			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		w.Header().Set("content-type", "application/json")
-		w.Write(bytes)
+		writeList(w, r, bids, dto.NewReviewerBid, method)
 
 	}
 }
 
-func BidStatus(ctx context.Context, s Storage) http.HandlerFunc {
+func BidStatus(s Storage) http.HandlerFunc {
 	method := "bid status"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -472,31 +537,32 @@ func BidStatus(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		bids, err := s.ReadBidStatus(ctx, bidId, username)
+		bid, err := s.ReadBid(r.Context(), bidId, username)
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(bids)
+		bytes, err := json.Marshal(bid.Status)
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(bid.Version))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func UpdateBidStatus(ctx context.Context, s Storage) http.HandlerFunc {
+func UpdateBidStatus(s Storage) http.HandlerFunc {
 	method := "update bid status"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -513,31 +579,32 @@ func UpdateBidStatus(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		bid, err := s.UpdateBidStatus(ctx, bidId, username, status)
+		bid, err := s.UpdateBidStatus(r.Context(), bidId, username, status, ifMatch(r))
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(bid)
+		bytes, err := json.Marshal(dto.NewOwnerBid(bid))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(bid.Version))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func EditBid(ctx context.Context, s Storage) http.HandlerFunc {
+func EditBid(s Storage) http.HandlerFunc {
 	method := "edit bid"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -548,9 +615,9 @@ func EditBid(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
@@ -567,25 +634,29 @@ func EditBid(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		bid, err := s.UpdateBid(ctx, bidId, username, b)
+		bid, err := s.UpdateBid(r.Context(), bidId, username, b, ifMatch(r))
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err = json.Marshal(bid)
+		bytes, err = json.Marshal(dto.NewOwnerBid(bid))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(bid.Version))
+		if bid.OutOfBudget {
+			w.Header().Set("warning", outOfBudgetWarning)
+		}
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func SubmitDecision(ctx context.Context, s Storage) http.HandlerFunc {
+func SubmitDecision(s Storage) http.HandlerFunc {
 	method := "submit decosion"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -602,19 +673,54 @@ func SubmitDecision(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		bid, err := s.SubmitDecision(r.Context(), bidId, decision, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.NewReviewerBid(bid))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func BidDecisions(s Storage) http.HandlerFunc {
+	method := "bid decisions"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		bid, err := s.SubmitDecision(ctx, bidId, decision, username)
+		tally, err := s.ReadBidDecisions(r.Context(), bidId, username)
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(bid)
+		bytes, err := json.Marshal(dto.NewDecisionTally(tally))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
@@ -626,7 +732,7 @@ func SubmitDecision(ctx context.Context, s Storage) http.HandlerFunc {
 	}
 }
 
-func Feedback(ctx context.Context, s Storage) http.HandlerFunc {
+func Feedback(s Storage) http.HandlerFunc {
 	method := "feedback"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -643,19 +749,19 @@ func Feedback(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		bid, err := s.Feedback(ctx, bidId, feedback, username)
+		bid, err := s.Feedback(r.Context(), bidId, feedback, username)
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(bid)
+		bytes, err := json.Marshal(dto.NewReviewerBid(bid))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
@@ -666,7 +772,7 @@ func Feedback(ctx context.Context, s Storage) http.HandlerFunc {
 
 	}
 }
-func RollbackBid(ctx context.Context, s Storage) http.HandlerFunc {
+func RollbackBid(s Storage) http.HandlerFunc {
 	method := "rollback bid version"
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -683,77 +789,67 @@ func RollbackBid(ctx context.Context, s Storage) http.HandlerFunc {
 			return
 		}
 
-		username := r.URL.Query().Get("username")
-		if len(username) == 0 {
-			writeErrorResponse(w, ErrPassUsername, 401, method)
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		bid, err := s.RollbackBid(ctx, bidId, version, username)
+		bid, err := s.RollbackBid(r.Context(), bidId, version, username, ifMatch(r))
 		if err != nil {
 			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		bytes, err := json.Marshal(bid)
+		bytes, err := json.Marshal(dto.NewOwnerBid(bid))
 		if err != nil {
 			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
+		w.Header().Set("etag", etag(bid.Version))
 		w.Header().Set("content-type", "application/json")
 		w.Write(bytes)
 
 	}
 }
 
-func ReviewsBids(ctx context.Context, s Storage) http.HandlerFunc {
+func ReviewsBids(s Storage) http.HandlerFunc {
 	method := "reviews bids"
 
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		tenderId, err := uuid.FromString(chi.URLParam(r, "tenderId"))
 		if err != nil {
-			log.Println(err)
+			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
 		authorUsername := r.URL.Query().Get("authorUsername")
 		if len(authorUsername) == 0 {
-			log.Println(err)
+			writeErrorResponse(w, ErrPassAuthor, 400, method)
 			return
 		}
 
-		requesterUsername := r.URL.Query().Get("requesterUsername")
-		if len(requesterUsername) == 0 {
-			log.Println(err)
+		requesterUsername, err := callerUsername(r, r.URL.Query().Get("requesterUsername"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
 			return
 		}
 
-		limit := 5
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
-			limit = tmp
-		}
-
-		offset := 0
-		if tmp, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
-			offset = tmp
-		}
-
-		bid, err := s.BidReviews(ctx, tenderId, authorUsername, requesterUsername, limit, offset)
+		opts, err := listOptions(r, model.SortCreatedAt, model.FeedbackSorts)
 		if err != nil {
-			writeErrorResponse(w, err, errStatusCode(err), method)
+			writeErrorResponse(w, err, 400, method)
 			return
 		}
 
-		bytes, err := json.Marshal(bid)
+		reviews, err := s.BidReviews(r.Context(), tenderId, authorUsername, requesterUsername, opts)
 		if err != nil {
-			writeErrorResponse(w, err, 400, method)
+			writeErrorResponse(w, err, errStatusCode(err), method)
 			return
 		}
 
-		w.Header().Set("content-type", "application/json")
-		w.Write(bytes)
+		writeList(w, r, reviews, dto.NewBidReview, method)
 
 	}
 }
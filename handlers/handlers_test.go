@@ -0,0 +1,305 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"zadanie/handlers"
+	"zadanie/memory"
+	"zadanie/model"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+type fixture struct {
+	router http.Handler
+	org    uuid.UUID
+	author uuid.UUID
+}
+
+func newFixture(t *testing.T) fixture {
+
+	s := memory.NewStorage()
+
+	org := s.AddOrganization("org")
+	s.AddResponsible(org, s.AddEmployee("owner"))
+	s.AddResponsible(org, s.AddEmployee("a1"), model.RoleApprover)
+	s.AddResponsible(org, s.AddEmployee("a2"), model.RoleApprover)
+	s.AddResponsible(org, s.AddEmployee("viewer"), model.RoleViewer)
+
+	authorOrg := s.AddOrganization("author org")
+	author := s.AddEmployee("author")
+	s.AddResponsible(authorOrg, author)
+
+	s.AddEmployee("outsider")
+
+	r := chi.NewRouter()
+	r.Use(handlers.Authenticate(nil, true))
+	r.Get("/tenders", handlers.Tenders(s))
+	r.Post("/tenders/new", handlers.NewTender(s))
+	r.Get("/tenders/{tenderId}/status", handlers.TenderStatus(s))
+	r.Put("/tenders/{tenderId}/status", handlers.UpdateTenderStatus(s))
+	r.Patch("/tenders/{tenderId}/edit", handlers.EditTender(s))
+	r.Put("/tenders/{tenderId}/rollback/{version}", handlers.RollbackTender(s))
+	r.Get("/tenders/{tenderId}/versions", handlers.TenderVersions(s))
+	r.Get("/tenders/{tenderId}/versions/{version}", handlers.TenderVersion(s))
+	r.Post("/bids/new", handlers.NewBid(s))
+	r.Put("/bids/{bidId}/status", handlers.UpdateBidStatus(s))
+	r.Put("/bids/{bidId}/submit_decision", handlers.SubmitDecision(s))
+
+	return fixture{router: r, org: org, author: author}
+
+}
+
+func (f fixture) do(t *testing.T, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+
+	t.Helper()
+
+	var r *http.Request
+	if len(body) != 0 {
+		r = httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+	} else {
+		r = httptest.NewRequest(method, path, nil)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, r)
+
+	return w
+
+}
+
+func (f fixture) expect(t *testing.T, w *httptest.ResponseRecorder, status int, v any) {
+
+	t.Helper()
+
+	if w.Code != status {
+		t.Fatalf("status %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+	if v != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("%v: %s", err, w.Body.String())
+		}
+	}
+
+}
+
+type tenderView struct {
+	Id      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Status  string    `json:"status"`
+	Version int       `json:"version"`
+}
+
+type bidView struct {
+	Id      uuid.UUID `json:"id"`
+	Status  string    `json:"status"`
+	Version int       `json:"version"`
+}
+
+func (f fixture) tender(t *testing.T, body string) tenderView {
+
+	t.Helper()
+
+	if len(body) == 0 {
+		body = fmt.Sprintf(`{"name":"t","description":"d","serviceType":"Delivery","organizationId":"%s","creatorUsername":"owner"}`, f.org)
+	}
+
+	var tender tenderView
+	f.expect(t, f.do(t, "POST", "/tenders/new", body), 200, &tender)
+	f.expect(t, f.do(t, "PUT", "/tenders/"+tender.Id.String()+"/status?status=Published&username=owner", ""), 200, &tender)
+
+	return tender
+
+}
+
+func (f fixture) bid(t *testing.T, tenderId uuid.UUID) bidView {
+
+	t.Helper()
+
+	body := fmt.Sprintf(`{"name":"b","description":"d","tenderId":"%s","authorType":"User","authorId":"%s"}`, tenderId, f.author)
+
+	var bid bidView
+	f.expect(t, f.do(t, "POST", "/bids/new", body), 200, &bid)
+	f.expect(t, f.do(t, "PUT", "/bids/"+bid.Id.String()+"/status?status=Published&username=author", ""), 200, &bid)
+
+	return bid
+
+}
+
+func TestTenderVersions(t *testing.T) {
+
+	f := newFixture(t)
+	tender := f.tender(t, "")
+	path := "/tenders/" + tender.Id.String()
+
+	if tender.Version != 2 {
+		t.Fatalf("published tender version %d, want 2", tender.Version)
+	}
+
+	f.expect(t, f.do(t, "PATCH", path+"/edit?username=owner", `{"name":"renamed"}`, "If-Match", `"1"`), 412, nil)
+	f.expect(t, f.do(t, "PATCH", path+"/edit?username=owner", `{"name":"renamed"}`, "If-Match", `"2"`), 200, &tender)
+	if tender.Name != "renamed" || tender.Version != 3 {
+		t.Fatalf("edited tender %+v", tender)
+	}
+
+	var versions []tenderView
+	f.expect(t, f.do(t, "GET", path+"/versions?username=owner", ""), 200, &versions)
+	if len(versions) != 3 {
+		t.Fatalf("%d versions, want 3", len(versions))
+	}
+
+	f.expect(t, f.do(t, "PUT", path+"/rollback/1?username=owner", ""), 200, &tender)
+	if tender.Name != "t" || tender.Status != "Created" || tender.Version != 4 {
+		t.Fatalf("rolled back tender %+v", tender)
+	}
+
+	f.expect(t, f.do(t, "GET", path+"/versions/9?username=owner", ""), 404, nil)
+
+}
+
+func TestTenderErrors(t *testing.T) {
+
+	f := newFixture(t)
+	tender := f.tender(t, "")
+	path := "/tenders/" + tender.Id.String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"no username", "GET", path + "/status", "", 401},
+		{"unknown user", "GET", path + "/versions?username=ghost", "", 401},
+		{"outsider", "PUT", path + "/status?status=Closed&username=outsider", "", 403},
+		{"viewer", "PUT", path + "/status?status=Closed&username=viewer", "", 403},
+		{"unknown status", "PUT", path + "/status?status=Archived&username=owner", "", 400},
+		{"malformed id", "GET", "/tenders/42/status?username=owner", "", 400},
+		{"unknown tender", "GET", "/tenders/" + uuid.Must(uuid.NewV4()).String() + "/status?username=owner", "", 404},
+		{"empty edit", "PATCH", path + "/edit?username=owner", `{}`, 400},
+		{"malformed version", "PUT", path + "/rollback/x?username=owner", "", 400},
+		{"unknown version", "PUT", path + "/rollback/9?username=owner", "", 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expect(t, f.do(t, tt.method, tt.path, tt.body), tt.status, nil)
+		})
+	}
+
+}
+
+func TestSubmitDecision(t *testing.T) {
+
+	f := newFixture(t)
+	tender := f.tender(t, "")
+	bid := f.bid(t, tender.Id)
+	path := "/bids/" + bid.Id.String() + "/submit_decision"
+
+	steps := []struct {
+		username string
+		decision string
+		status   int
+		bid      string
+	}{
+		{"owner", "Approved", 200, "Published"},
+		{"owner", "Approved", 200, "Published"},
+		{"owner", "Rejected", 409, ""},
+		{"outsider", "Approved", 403, ""},
+		{"viewer", "Approved", 403, ""},
+		{"a1", "Approved", 200, "Published"},
+		{"a2", "Approved", 200, "Approved"},
+		{"a2", "Approved", 200, "Approved"},
+	}
+
+	for i, step := range steps {
+		var got bidView
+		w := f.do(t, "PUT", fmt.Sprintf("%s?decision=%s&username=%s", path, step.decision, step.username), "")
+		if step.status != 200 {
+			f.expect(t, w, step.status, nil)
+			continue
+		}
+		f.expect(t, w, 200, &got)
+		if got.Status != step.bid {
+			t.Fatalf("step %d: bid %s, want %s", i, got.Status, step.bid)
+		}
+	}
+
+	var status string
+	f.expect(t, f.do(t, "GET", "/tenders/"+tender.Id.String()+"/status?username=owner", ""), 200, &status)
+	if status != "Closed" {
+		t.Fatalf("tender %s after award, want Closed", status)
+	}
+
+}
+
+func TestSubmitDecisionVeto(t *testing.T) {
+
+	f := newFixture(t)
+	tender := f.tender(t, "")
+	vetoed := f.bid(t, tender.Id)
+	other := f.bid(t, tender.Id)
+
+	var got bidView
+	f.expect(t, f.do(t, "PUT", "/bids/"+vetoed.Id.String()+"/submit_decision?decision=Rejected&username=a1", ""), 200, &got)
+	if got.Status != "Rejected" {
+		t.Fatalf("vetoed bid %s, want Rejected", got.Status)
+	}
+
+	f.expect(t, f.do(t, "PUT", "/bids/"+other.Id.String()+"/submit_decision?decision=Approved&username=a1", ""), 200, &got)
+	if got.Status != "Published" {
+		t.Fatalf("other bid %s after one approval, want Published", got.Status)
+	}
+
+}
+
+func TestHostileListParameters(t *testing.T) {
+
+	f := newFixture(t)
+	f.tender(t, "")
+
+	cursor := model.Cursor{Sort: "name; DROP TABLE tender; --", Key: "x", Id: uuid.Must(uuid.NewV4())}.Encode()
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"sort", "sort=" + url.QueryEscape("name; DROP TABLE tender; --"), 400},
+		{"quoted sort", "sort=" + url.QueryEscape(`name"`), 400},
+		{"cursor sort", "cursor=" + cursor, 400},
+		{"service type", "service_type=" + url.QueryEscape("Delivery' OR '1'='1"), 400},
+		{"unicode service type", "service_type=" + url.QueryEscape("Доставка"), 400},
+		{"plain", "sort=-name&service_type=Delivery", 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.expect(t, f.do(t, "GET", "/tenders?"+tt.query, ""), tt.status, nil)
+		})
+	}
+
+}
+
+func TestNewBidWithoutToken(t *testing.T) {
+
+	r := chi.NewRouter()
+	r.Use(handlers.Authenticate(nil, false))
+	r.Post("/bids/new", handlers.NewBid(memory.NewStorage()))
+	f := fixture{router: r, author: uuid.Must(uuid.NewV4())}
+
+	body := fmt.Sprintf(`{"name":"b","description":"d","tenderId":"%s","authorType":"User","authorId":"%s"}`, uuid.Must(uuid.NewV4()), f.author)
+	f.expect(t, f.do(t, "POST", "/bids/new", body), 401, nil)
+
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"zadanie/dto"
+	"zadanie/model"
+)
+
+const (
+	defaultLimit = 5
+	maxLimit     = 50
+)
+
+func pagination(query url.Values) (limit, offset int, err error) {
+
+	limit = defaultLimit
+	if query.Has("limit") {
+		if limit, err = strconv.Atoi(query.Get("limit")); err != nil || limit < 0 {
+			return 0, 0, ErrIncorrectPagination
+		}
+	}
+
+	if query.Has("offset") {
+		if offset, err = strconv.Atoi(query.Get("offset")); err != nil || offset < 0 {
+			return 0, 0, ErrIncorrectPagination
+		}
+	}
+
+	return min(limit, maxLimit), offset, nil
+
+}
+
+func listOptions(r *http.Request, sort model.Sort, sorts []string) (model.ListOptions, error) {
+
+	query := r.URL.Query()
+	opts := model.ListOptions{Sort: sort}
+
+	var err error
+	if opts.Limit, opts.Offset, err = pagination(query); err != nil {
+		return model.ListOptions{}, err
+	}
+
+	if query.Has("cursor") && opts.Offset != 0 {
+		return model.ListOptions{}, ErrCursorWithOffset
+	}
+
+	if token := query.Get("cursor"); len(token) != 0 {
+		c, ok := model.DecodeCursor(token)
+		if !ok {
+			return model.ListOptions{}, ErrIncorrectCursor
+		}
+		opts.After = &c
+		opts.Sort = c.Sort
+	}
+
+	if tmp := query.Get("sort"); len(tmp) != 0 {
+		if opts.After != nil && opts.After.Sort != model.Sort(tmp) {
+			return model.ListOptions{}, ErrIncorrectCursor
+		}
+		opts.Sort = model.Sort(tmp)
+	}
+
+	if !opts.Sort.Validate(sorts) {
+		return model.ListOptions{}, ErrIncorrectSort
+	}
+
+	opts.Total, _ = strconv.ParseBool(query.Get("total"))
+
+	return opts, nil
+
+}
+
+func writeList[T model.Listable, V any](w http.ResponseWriter, r *http.Request, page model.Page[T], view func(T) V, method string) {
+
+	var body any = dto.List(page.Items, view)
+	if r.URL.Query().Has("cursor") || page.Total != nil {
+		body = dto.NewPage(page, view)
+	}
+
+	bytes, err := json.Marshal(body)
+	if err != nil {
+		writeErrorResponse(w, err, 400, method)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(bytes)
+
+}
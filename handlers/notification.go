@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"zadanie/dto"
+	"zadanie/model"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+func Notifications(s Storage) http.HandlerFunc {
+	method := "notifications"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter := model.NotificationFilter{}
+		filter.Unread, _ = strconv.ParseBool(r.URL.Query().Get("unread"))
+
+		opts, err := listOptions(r, "-"+model.SortCreatedAt, model.NotificationSorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		inbox, err := s.ReadNotifications(r.Context(), username, filter, opts)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeList(w, r, inbox, dto.NewNotification, method)
+
+	}
+}
+
+func ReadNotification(s Storage) http.HandlerFunc {
+	method := "read notification"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		notificationId, err := uuid.FromString(chi.URLParam(r, "notificationId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		n, err := s.MarkNotificationRead(r.Context(), username, notificationId)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.NewNotification(n))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func ReadAllNotifications(s Storage) http.HandlerFunc {
+	method := "read all notifications"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		marked, err := s.MarkNotificationsRead(r.Context(), username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(map[string]int{"marked": marked})
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func Subscriptions(s Storage) http.HandlerFunc {
+	method := "subscriptions"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		subs, err := s.ReadSubscriptions(r.Context(), username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.List(subs, dto.NewSubscription))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func UpdateSubscription(s Storage) http.HandlerFunc {
+	method := "update subscription"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+		_ = r.Body.Close()
+
+		sub := model.Subscription{}
+		if err := json.Unmarshal(bytes, &sub); err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		sub.Channel = model.NotificationChannel(chi.URLParam(r, "channel"))
+		if !sub.Validate() {
+			writeErrorResponse(w, ErrIncorrectSubscription, 400, method)
+			return
+		}
+
+		sub, err = s.UpdateSubscription(r.Context(), username, sub.Normalize())
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err = json.Marshal(dto.NewSubscription(sub))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
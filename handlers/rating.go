@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"zadanie/dto"
+	"zadanie/model"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+func reputationFilter(r *http.Request) (model.ReputationFilter, error) {
+
+	query := r.URL.Query()
+	filter := model.ReputationFilter{Author: query.Get("authorUsername")}
+
+	if tmp := query.Get("organizationId"); len(tmp) != 0 {
+		orgId, err := uuid.FromString(tmp)
+		if err != nil {
+			return model.ReputationFilter{}, err
+		}
+		filter.OrganizationId = &orgId
+	}
+
+	if (len(filter.Author) == 0) == (filter.OrganizationId == nil) {
+		return model.ReputationFilter{}, ErrPassReputationSubject
+	}
+
+	return filter, nil
+
+}
+
+func RateBid(s Storage) http.HandlerFunc {
+	method := "rate bid"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+		_ = r.Body.Close()
+
+		rating := model.BidRating{}
+		if err := json.Unmarshal(bytes, &rating); err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		if !rating.ValidateScores() {
+			writeErrorResponse(w, ErrIncorrectScore, 400, method)
+			return
+		}
+
+		rating, err = s.RateBid(r.Context(), bidId, username, rating)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err = json.Marshal(dto.NewBidRating(rating))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(bytes)
+
+	}
+}
+
+func Reputation(s Storage) http.HandlerFunc {
+	method := "reputation"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter, err := reputationFilter(r)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		reputation, err := s.ReadReputation(r.Context(), username, filter)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.NewReputation(reputation))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func Ratings(s Storage) http.HandlerFunc {
+	method := "ratings"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		filter, err := reputationFilter(r)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		opts, err := listOptions(r, "-"+model.SortCreatedAt, model.RatingSorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		ratings, err := s.ReadRatings(r.Context(), username, filter, opts)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeList(w, r, ratings, dto.NewBidRating, method)
+
+	}
+}
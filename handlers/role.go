@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"zadanie/dto"
+	"zadanie/model"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+func Roles(s Storage) http.HandlerFunc {
+	method := "roles"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		orgId, err := uuid.FromString(chi.URLParam(r, "organizationId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		roles, err := s.ReadRoles(r.Context(), orgId, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.List(roles, dto.NewRoleAssignment))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func GrantRole(s Storage) http.HandlerFunc {
+	method := "grant role"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		orgId, err := uuid.FromString(chi.URLParam(r, "organizationId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		role := model.Role(chi.URLParam(r, "role"))
+		if !role.Validate() {
+			writeErrorResponse(w, ErrIncorrectRole, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		assignment, err := s.GrantRole(r.Context(), orgId, username, chi.URLParam(r, "member"), role)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.NewRoleAssignment(assignment))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func RevokeRole(s Storage) http.HandlerFunc {
+	method := "revoke role"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		orgId, err := uuid.FromString(chi.URLParam(r, "organizationId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		role := model.Role(chi.URLParam(r, "role"))
+		if !role.Validate() {
+			writeErrorResponse(w, ErrIncorrectRole, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		if err := s.RevokeRole(r.Context(), orgId, username, chi.URLParam(r, "member"), role); err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	}
+}
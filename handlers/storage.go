@@ -11,6 +11,13 @@ type Storage interface {
 	Pinger
 	Tenderer
 	Bidder
+	Threader
+	Rater
+	Roler
+	Auditor
+	Notifier
+	Webhooker
+	Watcher
 }
 
 type Pinger interface {
@@ -19,23 +26,72 @@ type Pinger interface {
 
 type Tenderer interface {
 	CreateTender(ctx context.Context, tender model.Tender, username string) (model.Tender, error)
-	ReadTenders(ctx context.Context, limit int, offset int, types []model.TenderServiceType) ([]model.Tender, error)
-	ReadMyTenders(ctx context.Context, username string, limit int, offset int) ([]model.Tender, error)
-	ReadTenderStatus(ctx context.Context, tenderId uuid.UUID, username string) (model.TenderStatus, error)
-	UpdateTender(ctx context.Context, tenderId uuid.UUID, username string, new model.Tender) (model.Tender, error)
-	UpdateTenderStatus(ctx context.Context, tenderId uuid.UUID, username string, status model.TenderStatus) (model.Tender, error)
-	RollbackTender(ctx context.Context, tenderId uuid.UUID, username string, ver int) (model.Tender, error)
+	ReadTenders(ctx context.Context, opts model.ListOptions, types []model.TenderServiceType) (model.Page[model.Tender], error)
+	SearchTenders(ctx context.Context, search string, opts model.ListOptions, types []model.TenderServiceType) (model.Page[model.TenderMatch], error)
+	ReadMyTenders(ctx context.Context, username string, opts model.ListOptions) (model.Page[model.Tender], error)
+	ReadTender(ctx context.Context, tenderId uuid.UUID, username string) (model.Tender, error)
+	UpdateTender(ctx context.Context, tenderId uuid.UUID, username string, new model.Tender, match model.Versions) (model.Tender, error)
+	UpdateTenderStatus(ctx context.Context, tenderId uuid.UUID, username string, status model.TenderStatus, match model.Versions) (model.Tender, error)
+	RollbackTender(ctx context.Context, tenderId uuid.UUID, username string, ver int, match model.Versions) (model.Tender, error)
+	ReadTenderVersions(ctx context.Context, tenderId uuid.UUID, username string) ([]model.Tender, error)
+	ReadTenderVersion(ctx context.Context, tenderId uuid.UUID, username string, ver int) (model.Tender, error)
 }
 
 type Bidder interface {
 	CreateBid(ctx context.Context, b model.Bid) (model.Bid, error)
-	ReadBids(ctx context.Context, tenderId uuid.UUID, username string, limit int, offset int) ([]model.Bid, error)
-	ReadMyBids(ctx context.Context, username string, limit int, offset int) ([]model.Bid, error)
-	ReadBidStatus(ctx context.Context, bidId uuid.UUID, username string) (model.BidStatus, error)
-	UpdateBid(ctx context.Context, bidId uuid.UUID, username string, new model.Bid) (model.Bid, error)
-	UpdateBidStatus(ctx context.Context, bidId uuid.UUID, username string, status model.BidStatus) (model.Bid, error)
+	ReadBids(ctx context.Context, tenderId uuid.UUID, username string, opts model.ListOptions) (model.Page[model.Bid], error)
+	ReadMyBids(ctx context.Context, username string, opts model.ListOptions) (model.Page[model.Bid], error)
+	ReadBid(ctx context.Context, bidId uuid.UUID, username string) (model.Bid, error)
+	UpdateBid(ctx context.Context, bidId uuid.UUID, username string, new model.Bid, match model.Versions) (model.Bid, error)
+	UpdateBidStatus(ctx context.Context, bidId uuid.UUID, username string, status model.BidStatus, match model.Versions) (model.Bid, error)
 	SubmitDecision(ctx context.Context, bidId uuid.UUID, decision model.BidStatus, username string) (model.Bid, error)
 	Feedback(ctx context.Context, bidId uuid.UUID, feedback string, username string) (model.Bid, error)
-	BidReviews(ctx context.Context, tenderId uuid.UUID, authorUsername string, requesterUsername string, limit int, offset int) ([]model.BidFeedback, error)
-	RollbackBid(ctx context.Context, bidId uuid.UUID, version int, username string) (model.Bid, error)
+	BidReviews(ctx context.Context, tenderId uuid.UUID, authorUsername string, requesterUsername string, opts model.ListOptions) (model.Page[model.BidFeedback], error)
+	RollbackBid(ctx context.Context, bidId uuid.UUID, version int, username string, match model.Versions) (model.Bid, error)
+	ReadBidVersions(ctx context.Context, bidId uuid.UUID, username string) ([]model.Bid, error)
+	ReadBidVersion(ctx context.Context, bidId uuid.UUID, version int, username string) (model.Bid, error)
+	ReadBidDecisions(ctx context.Context, bidId uuid.UUID, username string) (model.DecisionTally, error)
+}
+
+type Threader interface {
+	ReadBidThread(ctx context.Context, bidId uuid.UUID, username string, opts model.ListOptions) (model.Page[model.BidFeedback], error)
+	PostBidMessage(ctx context.Context, bidId uuid.UUID, username string, message model.BidFeedback) (model.BidFeedback, error)
+	EditBidMessage(ctx context.Context, bidId, messageId uuid.UUID, username string, text string) (model.BidFeedback, error)
+	DeleteBidMessage(ctx context.Context, bidId, messageId uuid.UUID, username string) error
+}
+
+type Rater interface {
+	RateBid(ctx context.Context, bidId uuid.UUID, username string, rating model.BidRating) (model.BidRating, error)
+	ReadReputation(ctx context.Context, username string, filter model.ReputationFilter) (model.Reputation, error)
+	ReadRatings(ctx context.Context, username string, filter model.ReputationFilter, opts model.ListOptions) (model.Page[model.BidRating], error)
+}
+
+type Roler interface {
+	ReadRoles(ctx context.Context, orgId uuid.UUID, username string) ([]model.RoleAssignment, error)
+	GrantRole(ctx context.Context, orgId uuid.UUID, username string, target string, role model.Role) (model.RoleAssignment, error)
+	RevokeRole(ctx context.Context, orgId uuid.UUID, username string, target string, role model.Role) error
+}
+
+type Auditor interface {
+	ReadAudit(ctx context.Context, username string, filter model.AuditFilter, opts model.ListOptions) (model.Page[model.AuditEvent], error)
+}
+
+type Notifier interface {
+	ReadNotifications(ctx context.Context, username string, filter model.NotificationFilter, opts model.ListOptions) (model.Page[model.Notification], error)
+	MarkNotificationRead(ctx context.Context, username string, notificationId uuid.UUID) (model.Notification, error)
+	MarkNotificationsRead(ctx context.Context, username string) (int, error)
+	ReadSubscriptions(ctx context.Context, username string) ([]model.Subscription, error)
+	UpdateSubscription(ctx context.Context, username string, sub model.Subscription) (model.Subscription, error)
+}
+
+type Webhooker interface {
+	CreateWebhook(ctx context.Context, username string, endpoint model.WebhookEndpoint) (model.WebhookEndpoint, error)
+	ReadWebhooks(ctx context.Context, username string) ([]model.WebhookEndpoint, error)
+	DeleteWebhook(ctx context.Context, username string, webhookId uuid.UUID) error
+	ReadDeliveries(ctx context.Context, username string, filter model.DeliveryFilter, opts model.ListOptions) (model.Page[model.WebhookDelivery], error)
+	Redeliver(ctx context.Context, username string, deliveryId uuid.UUID) (model.WebhookDelivery, error)
+}
+
+type Watcher interface {
+	WatchTender(ctx context.Context, tenderId uuid.UUID, username string) (<-chan model.TenderChange, error)
 }
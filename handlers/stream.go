@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"zadanie/dto"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+type shutdownKey struct{}
+
+func WithShutdown(ctx context.Context, shutdown <-chan struct{}) context.Context {
+	return context.WithValue(ctx, shutdownKey{}, shutdown)
+}
+
+func TenderEvents(s Storage) http.HandlerFunc {
+	method := "tender events"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		tenderId, err := uuid.FromString(chi.URLParam(r, "tenderId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeErrorResponse(w, ErrStreamingUnsupported, 500, method)
+			return
+		}
+
+		changes, err := s.WatchTender(r.Context(), tenderId, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		w.Header().Set("content-type", "text/event-stream")
+		w.Header().Set("cache-control", "no-cache")
+		w.Header().Set("x-accel-buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ": connected\n\n")
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		shutdown, _ := r.Context().Value(shutdownKey{}).(<-chan struct{})
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-shutdown:
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			case c, ok := <-changes:
+				if !ok {
+					return
+				}
+				bytes, err := json.Marshal(dto.NewTenderChange(c))
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", c.Id, c.Action, bytes)
+			}
+			flusher.Flush()
+		}
+
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"zadanie/dto"
+	"zadanie/model"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+func readMessage(r *http.Request) (model.BidFeedback, error) {
+
+	bytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		return model.BidFeedback{}, err
+	}
+	_ = r.Body.Close()
+
+	message := model.BidFeedback{}
+	if err := json.Unmarshal(bytes, &message); err != nil {
+		return model.BidFeedback{}, err
+	}
+
+	if len(message.Description) == 0 {
+		return model.BidFeedback{}, ErrPassFeedback
+	}
+
+	return message, nil
+
+}
+
+func writeMessage(w http.ResponseWriter, message model.BidFeedback, code int, method string) {
+
+	bytes, err := json.Marshal(dto.NewBidMessage(message))
+	if err != nil {
+		writeErrorResponse(w, err, 400, method)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(code)
+	w.Write(bytes)
+
+}
+
+func BidThread(s Storage) http.HandlerFunc {
+	method := "bid thread"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		opts, err := listOptions(r, model.SortCreatedAt, model.FeedbackSorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		thread, err := s.ReadBidThread(r.Context(), bidId, username, opts)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeList(w, r, thread, dto.NewBidMessage, method)
+
+	}
+}
+
+func PostBidMessage(s Storage) http.HandlerFunc {
+	method := "post bid message"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		message, err := readMessage(r)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		if len(message.Visibility) != 0 && !message.Visibility.Validate() {
+			writeErrorResponse(w, ErrIncorrectVisibility, 400, method)
+			return
+		}
+
+		message, err = s.PostBidMessage(r.Context(), bidId, username, message)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeMessage(w, message, http.StatusCreated, method)
+
+	}
+}
+
+func EditBidMessage(s Storage) http.HandlerFunc {
+	method := "edit bid message"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		messageId, err := uuid.FromString(chi.URLParam(r, "messageId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		message, err := readMessage(r)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		message, err = s.EditBidMessage(r.Context(), bidId, messageId, username, message.Description)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeMessage(w, message, http.StatusOK, method)
+
+	}
+}
+
+func DeleteBidMessage(s Storage) http.HandlerFunc {
+	method := "delete bid message"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		messageId, err := uuid.FromString(chi.URLParam(r, "messageId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		if err := s.DeleteBidMessage(r.Context(), bidId, messageId, username); err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	}
+}
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"zadanie/dto"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+func diffVersions(r *http.Request) (from, to int, err error) {
+
+	from, err = strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		return 0, 0, ErrPassVersions
+	}
+
+	to, err = strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		return 0, 0, ErrPassVersions
+	}
+
+	return from, to, nil
+
+}
+
+func TenderVersions(s Storage) http.HandlerFunc {
+	method := "tender versions"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		tenderId, err := uuid.FromString(chi.URLParam(r, "tenderId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		versions, err := s.ReadTenderVersions(r.Context(), tenderId, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.List(versions, dto.NewTenderVersion))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func TenderVersion(s Storage) http.HandlerFunc {
+	method := "tender version"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		tenderId, err := uuid.FromString(chi.URLParam(r, "tenderId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		version, err := strconv.Atoi(chi.URLParam(r, "version"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		tender, err := s.ReadTenderVersion(r.Context(), tenderId, username, version)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.NewTenderVersion(tender))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func TenderDiff(s Storage) http.HandlerFunc {
+	method := "tender diff"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		tenderId, err := uuid.FromString(chi.URLParam(r, "tenderId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		from, to, err := diffVersions(r)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		fromTender, err := s.ReadTenderVersion(r.Context(), tenderId, username, from)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		toTender, err := s.ReadTenderVersion(r.Context(), tenderId, username, to)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		changes, err := dto.Diff(dto.NewOwnerTender(fromTender), dto.NewOwnerTender(toTender))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		bytes, err := json.Marshal(changes)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func BidVersions(s Storage) http.HandlerFunc {
+	method := "bid versions"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		versions, err := s.ReadBidVersions(r.Context(), bidId, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.List(versions, dto.NewBidVersion))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func BidVersion(s Storage) http.HandlerFunc {
+	method := "bid version"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		version, err := strconv.Atoi(chi.URLParam(r, "version"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		bid, err := s.ReadBidVersion(r.Context(), bidId, version, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.NewBidVersion(bid))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func BidDiff(s Storage) http.HandlerFunc {
+	method := "bid diff"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		bidId, err := uuid.FromString(chi.URLParam(r, "bidId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		from, to, err := diffVersions(r)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		fromBid, err := s.ReadBidVersion(r.Context(), bidId, from, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		toBid, err := s.ReadBidVersion(r.Context(), bidId, to, username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		changes, err := dto.Diff(dto.NewOwnerBid(fromBid), dto.NewOwnerBid(toBid))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		bytes, err := json.Marshal(changes)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
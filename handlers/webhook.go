@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"zadanie/dto"
+	"zadanie/model"
+	"zadanie/webhook"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
+)
+
+func Webhooks(s Storage) http.HandlerFunc {
+	method := "webhooks"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		webhooks, err := s.ReadWebhooks(r.Context(), username)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.List(webhooks, dto.NewWebhookEndpoint))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Write(bytes)
+
+	}
+}
+
+func CreateWebhook(s Storage) http.HandlerFunc {
+	method := "create webhook"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		bytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+		_ = r.Body.Close()
+
+		endpoint := model.WebhookEndpoint{}
+		if err := json.Unmarshal(bytes, &endpoint); err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		if !endpoint.Validate() {
+			writeErrorResponse(w, ErrIncorrectWebhook, 400, method)
+			return
+		}
+
+		if err := webhook.CheckURL(r.Context(), endpoint.URL); err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		endpoint, err = s.CreateWebhook(r.Context(), username, endpoint)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		view := dto.NewWebhookEndpoint(endpoint)
+		view.Secret = &endpoint.Secret
+
+		bytes, err = json.Marshal(view)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(bytes)
+
+	}
+}
+
+func DeleteWebhook(s Storage) http.HandlerFunc {
+	method := "delete webhook"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		webhookId, err := uuid.FromString(chi.URLParam(r, "webhookId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		if err := s.DeleteWebhook(r.Context(), username, webhookId); err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	}
+}
+
+func WebhookDeliveries(s Storage) http.HandlerFunc {
+	method := "webhook deliveries"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		webhookId, err := uuid.FromString(chi.URLParam(r, "webhookId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		filter := model.DeliveryFilter{
+			EndpointId: webhookId,
+			Status:     model.DeliveryStatus(r.URL.Query().Get("status")),
+		}
+		if len(filter.Status) != 0 && !filter.Status.Validate() {
+			writeErrorResponse(w, ErrIncorrectDeliveryStatus, 400, method)
+			return
+		}
+
+		opts, err := listOptions(r, "-"+model.SortCreatedAt, model.DeliverySorts)
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		deliveries, err := s.ReadDeliveries(r.Context(), username, filter, opts)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		writeList(w, r, deliveries, dto.NewWebhookDelivery, method)
+
+	}
+}
+
+func Redeliver(s Storage) http.HandlerFunc {
+	method := "redeliver"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		deliveryId, err := uuid.FromString(chi.URLParam(r, "deliveryId"))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		username, err := callerUsername(r, r.URL.Query().Get("username"))
+		if err != nil {
+			writeErrorResponse(w, err, 401, method)
+			return
+		}
+
+		delivery, err := s.Redeliver(r.Context(), username, deliveryId)
+		if err != nil {
+			writeErrorResponse(w, err, errStatusCode(err), method)
+			return
+		}
+
+		bytes, err := json.Marshal(dto.NewWebhookDelivery(delivery))
+		if err != nil {
+			writeErrorResponse(w, err, 400, method)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(bytes)
+
+	}
+}
@@ -2,73 +2,140 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"zadanie/config"
 	"zadanie/handlers"
+	"zadanie/memory"
+	"zadanie/model"
+	"zadanie/notify"
+	"zadanie/scheduler"
 	"zadanie/storage"
+	"zadanie/webhook"
 
-	"github.com/go-chi/chi/v5"
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
-const PORT = ":8080"
+type closableStorage interface {
+	handlers.Storage
+	Employee(ctx context.Context, username string) (model.Employee, error)
+	scheduler.TenderCloser
+	notify.Directory
+	notify.InboxStorage
+	webhook.Outbox
+	SetBus(bus *notify.Bus)
+	ListenChanges(ctx context.Context)
+	Close()
+}
+
+func newStorage(ctx context.Context, cfg config.Config) (closableStorage, error) {
+
+	switch cfg.StorageBackend {
+	case config.BackendMemory:
+		s := memory.NewStorage()
+		if len(cfg.MemorySeed) != 0 {
+			if err := s.Seed(cfg.MemorySeed); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	default:
+		return storage.NewStorage(ctx, cfg.Postgres)
+	}
+
+}
 
 func main() {
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
-	storage, err := storage.NewStorage(ctx)
+	openapi3.SchemaErrorDetailsDisabled = true
+
+	if len(os.Args) > 1 {
+		commands := map[string]func(context.Context, []string) error{
+			"migrate": migrate,
+			"token":   token,
+		}
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(ctx, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	storage, err := newStorage(ctx, cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer storage.Close()
 
-	router := chi.NewRouter()
-
-	router.Route("/api", func(r chi.Router) {
-		r.Get("/ping", handlers.Ping(ctx, storage))
-
-		r.Route("/tenders", func(r chi.Router) {
-			r.Get("/", handlers.Tenders(ctx, storage))
-			r.Post("/new", handlers.NewTender(ctx, storage))
-			r.Get("/my", handlers.MyTenders(ctx, storage))
-			r.Get("/{tenderId}/status", handlers.TenderStatus(ctx, storage))
-			r.Put("/{tenderId}/status", handlers.UpdateTenderStatus(ctx, storage))
-			r.Patch("/{tenderId}/edit", handlers.EditTender(ctx, storage))
-			r.Put("/{tenderId}/rollback/{version}", handlers.RollbackTender(ctx, storage))
-		})
-
-		r.Route("/bids", func(r chi.Router) {
-			r.Post("/new", handlers.NewBid(ctx, storage))
-			r.Get("/my", handlers.MyBids(ctx, storage))
-			r.Get("/{tenderId}/list", handlers.BidsList(ctx, storage))
-			r.Get("/{tenderId}/reviews", handlers.ReviewsBids(ctx, storage))
-			r.Get("/{bidId}/status", handlers.BidStatus(ctx, storage))
-			r.Put("/{bidId}/status", handlers.UpdateBidStatus(ctx, storage))
-			r.Patch("/{bidId}/edit", handlers.EditBid(ctx, storage))
-			r.Put("/{bidId}/submit_decision", handlers.SubmitDecision(ctx, storage))
-			r.Put("/{bidId}/feedback", handlers.Feedback(ctx, storage))
-			r.Put("/{bidId}/rollback/{version}", handlers.RollbackBid(ctx, storage))
-
-		})
-	})
+	notifiers := []notify.Notifier{notify.NewInbox(storage)}
+	if len(cfg.Notify.SMTP.Address) != 0 {
+		email := notify.NewEmail(cfg.Notify.SMTP)
+		notifiers = append(notifiers, email)
+		go email.Run(ctx)
+	}
+
+	bus := notify.NewBus(storage, int(cfg.Notify.QueueSize), notifiers...)
+	storage.SetBus(bus)
+	go bus.Run(ctx)
+
+	router, err := newRouter(storage, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go scheduler.CloseExpiredTenders(ctx, storage, cfg.CloseInterval)
+	go webhook.Deliver(ctx, storage, cfg.Webhooks)
+	go storage.ListenChanges(ctx)
+
+	streams, stopStreams := context.WithCancel(context.Background())
+	defer stopStreams()
 
 	srv := &http.Server{
-		Addr:    PORT,
+		Addr:    cfg.ServerAddress,
 		Handler: router,
+		BaseContext: func(net.Listener) context.Context {
+			return handlers.WithShutdown(context.Background(), streams.Done())
+		},
 	}
+	srv.RegisterOnShutdown(stopStreams)
 
 	go func() {
-		log.Println(`server started on ":8080"`)
-		if err := srv.ListenAndServe(); err != nil {
+		log.Printf("server started on %q", cfg.ServerAddress)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatal(err)
 		}
 	}()
 
 	<-ctx.Done()
+	log.Println("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println(err)
+	}
+
+	if n := bus.Dropped(); n != 0 {
+		log.Printf("notify: %d events dropped since start", n)
+	}
 
 }
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"time"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) record(ctx context.Context, events ...model.AuditEvent) {
+
+	var requestId *string
+	if id := middleware.GetReqID(ctx); len(id) != 0 {
+		requestId = &id
+	}
+
+	for _, e := range events {
+		e.Id = uuid.Must(uuid.NewV4())
+		e.RequestId = requestId
+		e.CreatedAt = time.Now().UTC()
+		s.audit = append(s.audit, e)
+		s.enqueueWebhooks(e)
+		s.publishChange(e)
+		s.bus.Publish(e)
+	}
+
+}
+
+func (s *Storage) ReadAudit(ctx context.Context, username string, filter model.AuditFilter, opts model.ListOptions) (model.Page[model.AuditEvent], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Page[model.AuditEvent]{}, err
+	}
+
+	orgId, err := s.userOrgId(userId)
+	if err != nil {
+		return model.Page[model.AuditEvent]{}, err
+	}
+
+	if !s.checkPermission(userId, orgId, model.ActionView) {
+		return model.Page[model.AuditEvent]{}, storage.ErrNotEnoughPerm
+	}
+
+	var actorId *uuid.UUID
+	if len(filter.Actor) != 0 {
+		id, err := s.userId(filter.Actor)
+		if err != nil {
+			return model.Page[model.AuditEvent]{}, err
+		}
+		actorId = &id
+	}
+
+	events := []model.AuditEvent{}
+	for _, e := range s.audit {
+		if e.OrganizationId != orgId {
+			continue
+		}
+		if len(filter.EntityType) != 0 && e.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityId != nil && e.EntityId != *filter.EntityId {
+			continue
+		}
+		if actorId != nil && (e.ActorId == nil || *e.ActorId != *actorId) {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	return list(events, opts), nil
+
+}
@@ -0,0 +1,677 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) bid(id uuid.UUID) (model.Bid, error) {
+
+	bid, ok := s.bids[id]
+	if !ok {
+		return model.Bid{}, storage.ErrBidNotFound
+	}
+
+	return bid, nil
+
+}
+
+func (s *Storage) bidVer(id uuid.UUID, ver int) (model.Bid, error) {
+
+	for _, bid := range s.bidArchive[id] {
+		if bid.Version == ver {
+			return bid, nil
+		}
+	}
+
+	return model.Bid{}, storage.ErrVersionNotFound
+
+}
+
+func (s *Storage) saveBid(bid model.Bid) model.Bid {
+
+	s.bids[bid.Id] = bid
+	s.bidArchive[bid.Id] = append(s.bidArchive[bid.Id], bid)
+
+	return bid
+
+}
+
+func (s *Storage) bumpBid(bid model.Bid, by *uuid.UUID, op model.Operation) model.Bid {
+
+	bid.ModifiedBy = by
+	bid.Operation = op
+	bid.Version++
+	bid.UpdatedAt = time.Now().UTC()
+
+	return s.saveBid(bid)
+
+}
+
+func (s *Storage) CreateBid(ctx context.Context, b model.Bid) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkUser(b.AuthorId); err != nil {
+		return model.Bid{}, err
+	}
+
+	tender, err := s.tender(b.TenderId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if tender.Status != model.TenderStatusPublished && !s.checkRelationToOrganization(b.AuthorId, tender.OrganizationId) {
+		return model.Bid{}, storage.ErrNotEnoughPerm
+	}
+
+	now := time.Now().UTC()
+
+	if !tender.AcceptsBids(now) {
+		return model.Bid{}, storage.ErrSubmissionClosed
+	}
+
+	if !b.ValidateTerms(tender) {
+		return model.Bid{}, storage.ErrIncorrectTerms
+	}
+
+	outOfBudget := !tender.FitsBudget(b)
+	if outOfBudget && tender.BudgetPolicy == model.BudgetPolicyReject {
+		return model.Bid{}, storage.ErrPriceOutOfBudget
+	}
+
+	authorOrgId, err := s.userOrgId(b.AuthorId)
+	if err != nil {
+		authorOrgId = uuid.Nil
+	}
+
+	created := s.saveBid(model.Bid{
+		Id:           uuid.Must(uuid.NewV4()),
+		Name:         b.Name,
+		Description:  b.Description,
+		Status:       model.BidStatusCreated,
+		TenderId:     b.TenderId,
+		AuthorType:   b.AuthorType,
+		AuthorId:     b.AuthorId,
+		Price:        b.Price,
+		Currency:     b.Currency,
+		DeliveryDays: b.DeliveryDays,
+		ValidUntil:   utc(b.ValidUntil),
+		OutOfBudget:  outOfBudget,
+		ModifiedBy:   &b.AuthorId,
+		Operation:    model.OperationCreate,
+		Version:      1,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	s.record(ctx, model.BidEvent(model.AuditBidCreate, &b.AuthorId, authorOrgId, nil, created))
+
+	return created, nil
+
+}
+
+func (s *Storage) ReadMyBids(ctx context.Context, username string, opts model.ListOptions) (model.Page[model.Bid], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Page[model.Bid]{}, err
+	}
+
+	bids := []model.Bid{}
+	for _, bid := range s.bids {
+		if bid.AuthorId == userId {
+			bids = append(bids, bid)
+		}
+	}
+
+	return list(bids, opts), nil
+
+}
+
+func (s *Storage) ReadBids(ctx context.Context, tenderId uuid.UUID, username string, opts model.ListOptions) (model.Page[model.Bid], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return model.Page[model.Bid]{}, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Page[model.Bid]{}, err
+	}
+
+	userOrgId, err := s.userOrgId(userId)
+	if err != nil {
+		return model.Page[model.Bid]{}, err
+	}
+
+	bids := []model.Bid{}
+	for _, bid := range s.bids {
+		if bid.TenderId != tenderId {
+			continue
+		}
+		if s.checkRelationToOrganization(bid.AuthorId, userOrgId) ||
+			(tender.OrganizationId == userOrgId && bid.Status == model.BidStatusPublished) {
+			bids = append(bids, bid)
+		}
+	}
+
+	return list(bids, opts), nil
+
+}
+
+func (s *Storage) checkBidAccess(userId uuid.UUID, bid model.Bid) error {
+
+	bidOrgId, err := s.userOrgId(bid.AuthorId)
+	if err != nil {
+		return err
+	}
+
+	tender, err := s.tender(bid.TenderId)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case s.checkPermission(userId, bidOrgId, model.ActionView):
+	case s.checkPermission(userId, tender.OrganizationId, model.ActionView):
+		if bid.Status == model.BidStatusCreated || bid.Status == model.BidStatusCanceled {
+			return storage.ErrNotEnoughPerm
+		}
+	default:
+		return storage.ErrNotEnoughPerm
+	}
+
+	return nil
+
+}
+
+func (s *Storage) ReadBid(ctx context.Context, bidId uuid.UUID, username string) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if err := s.checkBidAccess(userId, bid); err != nil {
+		return model.Bid{}, err
+	}
+
+	return bid, nil
+
+}
+
+func (s *Storage) ReadBidVersions(ctx context.Context, bidId uuid.UUID, username string) ([]model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBidAccess(userId, bid); err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(s.bidArchive[bidId]), nil
+
+}
+
+func (s *Storage) ReadBidVersion(ctx context.Context, bidId uuid.UUID, version int, username string) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if err := s.checkBidAccess(userId, bid); err != nil {
+		return model.Bid{}, err
+	}
+
+	return s.bidVer(bidId, version)
+
+}
+
+func (s *Storage) UpdateBidStatus(ctx context.Context, bidId uuid.UUID, username string, status model.BidStatus, match model.Versions) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bidOrgId, err := s.userOrgId(bid.AuthorId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if !s.checkPermission(userId, bidOrgId, model.ActionEditBid) {
+		return model.Bid{}, storage.ErrNotEnoughPerm
+	}
+
+	if !match.Match(bid.Version) {
+		return model.Bid{}, storage.ErrVersionMismatch
+	}
+
+	if bid.Status == model.BidStatusCanceled {
+		return model.Bid{}, storage.ErrStatusCantBeChanged
+	}
+
+	bid.Status = status
+
+	updated := s.bumpBid(bid, &userId, model.OperationStatusChange)
+	s.record(ctx, model.BidEvent(model.AuditBidStatus, &userId, bidOrgId, &bid, updated))
+
+	return updated, nil
+
+}
+
+func (s *Storage) UpdateBid(ctx context.Context, bidId uuid.UUID, username string, new model.Bid, match model.Versions) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bidOrgId, err := s.userOrgId(bid.AuthorId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if !s.checkPermission(userId, bidOrgId, model.ActionEditBid) {
+		return model.Bid{}, storage.ErrNotEnoughPerm
+	}
+
+	if !match.Match(bid.Version) {
+		return model.Bid{}, storage.ErrVersionMismatch
+	}
+
+	if len(new.Name) != 0 {
+		bid.Name = new.Name
+	}
+	if len(new.Description) != 0 {
+		bid.Description = new.Description
+	}
+	if new.Price != nil {
+		bid.Price = new.Price
+	}
+	if new.Currency != nil {
+		bid.Currency = new.Currency
+	}
+	if new.DeliveryDays != nil {
+		bid.DeliveryDays = new.DeliveryDays
+	}
+	if new.ValidUntil != nil {
+		bid.ValidUntil = utc(new.ValidUntil)
+	}
+
+	tender, err := s.tender(bid.TenderId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if !bid.ValidateTerms(tender) {
+		return model.Bid{}, storage.ErrIncorrectTerms
+	}
+
+	bid.OutOfBudget = !tender.FitsBudget(bid)
+	if bid.OutOfBudget && tender.BudgetPolicy == model.BudgetPolicyReject {
+		return model.Bid{}, storage.ErrPriceOutOfBudget
+	}
+
+	updated := s.bumpBid(bid, &userId, model.OperationEdit)
+	s.record(ctx, model.BidEvent(model.AuditBidEdit, &userId, bidOrgId, &bid, updated))
+
+	return updated, nil
+
+}
+
+func (s *Storage) RollbackBid(ctx context.Context, bidId uuid.UUID, version int, username string, match model.Versions) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bidOrgId, err := s.userOrgId(bid.AuthorId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if !s.checkPermission(userId, bidOrgId, model.ActionEditBid) {
+		return model.Bid{}, storage.ErrNotEnoughPerm
+	}
+
+	if !match.Match(bid.Version) {
+		return model.Bid{}, storage.ErrVersionMismatch
+	}
+
+	oldBid, err := s.bidVer(bidId, version)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bid.Name = oldBid.Name
+	bid.Description = oldBid.Description
+	bid.Status = oldBid.Status
+	bid.Price = oldBid.Price
+	bid.Currency = oldBid.Currency
+	bid.DeliveryDays = oldBid.DeliveryDays
+	bid.ValidUntil = oldBid.ValidUntil
+	bid.OutOfBudget = oldBid.OutOfBudget
+
+	updated := s.bumpBid(bid, &userId, model.OperationRollback)
+	s.record(ctx, model.BidEvent(model.AuditBidRollback, &userId, bidOrgId, &bid, updated))
+
+	return updated, nil
+
+}
+
+func (s *Storage) SubmitDecision(ctx context.Context, bidId uuid.UUID, decision model.BidStatus, username string) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	tender, err := s.tender(bid.TenderId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionDecideBid) {
+		return model.Bid{}, storage.ErrNotEnoughPerm
+	}
+
+	var previous model.BidStatus
+	for _, d := range s.decisions[bidId] {
+		if d.UserId == userId {
+			previous = d.Decision
+		}
+	}
+
+	if previous == decision {
+		return bid, nil
+	}
+
+	if len(previous) != 0 {
+		return model.Bid{}, storage.ErrDecisionSubmitted
+	}
+
+	awarded := 0
+	for _, other := range s.bids {
+		if other.TenderId == tender.Id && other.Id != bidId && other.Status == model.BidStatusApproved {
+			awarded++
+		}
+	}
+
+	if awarded >= tender.Awards() {
+		return model.Bid{}, storage.ErrBidAwarded
+	}
+
+	if bid.Status != model.BidStatusPublished {
+		return model.Bid{}, storage.ErrStatusCantBeChanged
+	}
+
+	if tender.Status == model.TenderStatusClosed {
+		return model.Bid{}, storage.ErrTenderClosed
+	}
+
+	s.decisions[bidId] = append(s.decisions[bidId], model.BidDecision{
+		BidId:     bidId,
+		UserId:    userId,
+		Username:  username,
+		Decision:  decision,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	count := 0
+	for _, d := range s.decisions[bidId] {
+		if d.Decision == decision {
+			count++
+		}
+	}
+
+	voters := len(s.voters(tender.OrganizationId))
+
+	if decision == model.BidStatusRejected {
+		if count < tender.RequiredRejections(voters) {
+			s.record(ctx, model.BidEvent(model.AuditBidReject, &userId, tender.OrganizationId, &bid, bid))
+			return bid, nil
+		}
+
+		bid.Status = model.BidStatusRejected
+		updated := s.bumpBid(bid, &userId, model.OperationDecision)
+		s.record(ctx, model.BidEvent(model.AuditBidReject, &userId, tender.OrganizationId, &bid, updated))
+
+		return updated, nil
+	}
+
+	if count < tender.RequiredApprovals(voters) {
+		s.record(ctx, model.BidEvent(model.AuditBidApprove, &userId, tender.OrganizationId, &bid, bid))
+		return bid, nil
+	}
+
+	before := bid
+	bid.Status = model.BidStatusApproved
+	updBid := s.bumpBid(bid, &userId, model.OperationDecision)
+	s.record(ctx, model.BidEvent(model.AuditBidApprove, &userId, tender.OrganizationId, &before, updBid))
+
+	if awarded+1 < tender.Awards() {
+		return updBid, nil
+	}
+
+	prev := tender
+	reason := model.TenderCloseReasonAwarded
+	tender.Status = model.TenderStatusClosed
+	tender.CloseReason = &reason
+	closed := s.bumpTender(tender, &userId, model.OperationDecision)
+	s.record(ctx, model.TenderEvent(model.AuditTenderClose, &userId, &prev, closed))
+
+	s.rejectOpenBids(ctx, closed, &userId)
+
+	return updBid, nil
+
+}
+
+func (s *Storage) rejectOpenBids(ctx context.Context, tender model.Tender, actorId *uuid.UUID) {
+
+	for _, bid := range s.bids {
+		if bid.TenderId != tender.Id || bid.Status != model.BidStatusPublished {
+			continue
+		}
+
+		before := bid
+		bid.Status = model.BidStatusRejected
+		updated := s.bumpBid(bid, actorId, model.OperationClose)
+
+		s.feedback = append(s.feedback, model.BidFeedback{
+			Id:          uuid.Must(uuid.NewV4()),
+			TenderId:    tender.Id,
+			BidId:       bid.Id,
+			Description: tender.RejectionFeedback(),
+			CreatedAt:   time.Now().UTC(),
+			Side:        model.FeedbackSideSystem,
+			Visibility:  model.FeedbackVisibilityPublic,
+		})
+		s.record(ctx, model.BidEvent(model.AuditBidReject, actorId, tender.OrganizationId, &before, updated))
+	}
+
+}
+
+func (s *Storage) Feedback(ctx context.Context, bidId uuid.UUID, feedback string, username string) (model.Bid, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	tender, err := s.tender(bid.TenderId)
+	if err != nil {
+		return model.Bid{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionFeedback) {
+		return model.Bid{}, storage.ErrNotEnoughPerm
+	}
+
+	if bid.Status == model.BidStatusCreated || bid.Status == model.BidStatusCanceled {
+		return model.Bid{}, storage.ErrNotEnoughPerm
+	}
+
+	posted := model.BidFeedback{
+		Id:          uuid.Must(uuid.NewV4()),
+		TenderId:    tender.Id,
+		BidId:       bidId,
+		UserId:      &userId,
+		Description: feedback,
+		CreatedAt:   time.Now().UTC(),
+		Side:        model.FeedbackSideReviewer,
+		Visibility:  model.FeedbackVisibilityPublic,
+	}
+	s.feedback = append(s.feedback, posted)
+	s.record(ctx, model.BidEvent(model.AuditBidFeedback, &userId, tender.OrganizationId, &bid, bid).About(posted))
+
+	return bid, nil
+
+}
+
+func (s *Storage) BidReviews(ctx context.Context, tenderId uuid.UUID, authorUsername, requesterUsername string, opts model.ListOptions) (model.Page[model.BidFeedback], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return model.Page[model.BidFeedback]{}, err
+	}
+
+	authorId, err := s.userId(authorUsername)
+	if err != nil {
+		return model.Page[model.BidFeedback]{}, err
+	}
+
+	requesterId, err := s.userId(requesterUsername)
+	if err != nil {
+		return model.Page[model.BidFeedback]{}, err
+	}
+
+	if !s.checkPermission(requesterId, tender.OrganizationId, model.ActionView) {
+		return model.Page[model.BidFeedback]{}, storage.ErrNotEnoughPerm
+	}
+
+	reviews := []model.BidFeedback{}
+	for _, f := range s.feedback {
+		if f.TenderId != tenderId || f.Side == model.FeedbackSideAuthor || f.DeletedAt != nil {
+			continue
+		}
+		if bid, ok := s.bids[f.BidId]; ok && bid.AuthorId == authorId {
+			reviews = append(reviews, f)
+		}
+	}
+
+	return list(reviews, opts), nil
+
+}
+
+func (s *Storage) ReadBidDecisions(ctx context.Context, bidId uuid.UUID, username string) (model.DecisionTally, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.DecisionTally{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.DecisionTally{}, err
+	}
+
+	tender, err := s.tender(bid.TenderId)
+	if err != nil {
+		return model.DecisionTally{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionView) {
+		return model.DecisionTally{}, storage.ErrNotEnoughPerm
+	}
+
+	return model.NewDecisionTally(tender, slices.Clone(s.decisions[bidId]), s.voters(tender.OrganizationId)), nil
+
+}
@@ -0,0 +1,187 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"zadanie/memory"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestConcurrentApprovals(t *testing.T) {
+
+	ctx := context.Background()
+	s := memory.NewStorage()
+
+	org := s.AddOrganization("org")
+	s.AddResponsible(org, s.AddEmployee("owner"))
+
+	approvers := []string{}
+	for i := range 8 {
+		username := fmt.Sprintf("approver%d", i)
+		s.AddResponsible(org, s.AddEmployee(username))
+		if _, err := s.GrantRole(ctx, org, "owner", username, model.RoleApprover); err != nil {
+			t.Fatal(err)
+		}
+		approvers = append(approvers, username)
+	}
+
+	authorOrg := s.AddOrganization("author org")
+	author := s.AddEmployee("author")
+	s.AddResponsible(authorOrg, author)
+
+	tender, err := s.CreateTender(ctx, model.Tender{
+		Name:           "t",
+		Description:    "d",
+		ServiceType:    model.TenderServiceTypeDelivery,
+		OrganizationId: org,
+		ApprovalRule:   model.ApprovalRuleQuorum,
+	}, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateTenderStatus(ctx, tender.Id, "owner", model.TenderStatusPublished, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	bid, err := s.CreateBid(ctx, model.Bid{
+		Name:        "b",
+		Description: "d",
+		TenderId:    tender.Id,
+		AuthorType:  model.BidAuthorTypeUser,
+		AuthorId:    author,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bid, err = s.UpdateBidStatus(ctx, bid.Id, "author", model.BidStatusPublished, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	const repeats = 3
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([][repeats]error, len(approvers))
+
+	for i, username := range approvers {
+		for j := range repeats {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, errs[i][j] = s.SubmitDecision(ctx, bid.Id, model.BidStatusApproved, username)
+			}()
+		}
+	}
+
+	close(start)
+	wg.Wait()
+
+	voted := 0
+	for i, results := range errs {
+		for _, err := range results {
+			if err != nil && !errors.Is(err, storage.ErrStatusCantBeChanged) && !errors.Is(err, storage.ErrTenderClosed) {
+				t.Fatalf("%s: %v", approvers[i], err)
+			}
+			if (err == nil) != (results[0] == nil) {
+				t.Fatalf("%s: repeated decision gave %v and %v", approvers[i], results[0], err)
+			}
+		}
+		if results[0] == nil {
+			voted++
+		}
+	}
+
+	tally, err := s.ReadBidDecisions(ctx, bid.Id, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tally.Approvals != tally.RequiredApprovals || len(tally.Decisions) != tally.RequiredApprovals || voted != tally.RequiredApprovals {
+		t.Fatalf("%d approvals from %d voters, %d recorded, want exactly %d", tally.Approvals, voted, len(tally.Decisions), tally.RequiredApprovals)
+	}
+
+	versions, err := s.ReadBidVersions(ctx, bid.Id, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved := 0
+	for _, v := range versions {
+		if v.Status == model.BidStatusApproved {
+			approved++
+		}
+	}
+	if approved != 1 || versions[len(versions)-1].Version != bid.Version+1 {
+		t.Fatalf("bid approved in %d versions, last version %d", approved, versions[len(versions)-1].Version)
+	}
+
+	closes := 0
+	for _, e := range audit(t, s, tender.Id) {
+		if e.Action == model.AuditTenderClose {
+			closes++
+		}
+	}
+	if closes != 1 {
+		t.Fatalf("tender closed %d times", closes)
+	}
+
+}
+
+func audit(t *testing.T, s *memory.Storage, tenderId uuid.UUID) []model.AuditEvent {
+
+	page, err := s.ReadAudit(context.Background(), "owner", model.AuditFilter{EntityType: model.EntityTender, EntityId: &tenderId}, model.ListOptions{Sort: "created_at", Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return page.Items
+
+}
+
+func TestDraftBidAudit(t *testing.T) {
+
+	ctx := context.Background()
+	s := memory.NewStorage()
+
+	org := s.AddOrganization("org")
+	s.AddResponsible(org, s.AddEmployee("owner"))
+	author := s.AddEmployee("author")
+
+	tender, err := s.CreateTender(ctx, model.Tender{
+		Name:           "t",
+		Description:    "d",
+		ServiceType:    model.TenderServiceTypeDelivery,
+		OrganizationId: org,
+	}, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateTenderStatus(ctx, tender.Id, "owner", model.TenderStatusPublished, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	bid, err := s.CreateBid(ctx, model.Bid{
+		Name:        "b",
+		Description: "d",
+		TenderId:    tender.Id,
+		AuthorType:  model.BidAuthorTypeUser,
+		AuthorId:    author,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := s.ReadAudit(ctx, "owner", model.AuditFilter{EntityType: model.EntityBid, EntityId: &bid.Id}, model.ListOptions{Sort: "created_at", Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 0 {
+		t.Fatalf("tender organization sees %d events of a draft bid", len(page.Items))
+	}
+
+}
@@ -0,0 +1,5 @@
+package memory
+
+import "errors"
+
+var ErrIncorrectSeed = errors.New("seed assigns an incorrect role")
@@ -0,0 +1,358 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"zadanie/model"
+	"zadanie/notify"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+type responsible struct {
+	OrganizationId uuid.UUID `json:"organizationId"`
+	UserId         uuid.UUID `json:"userId"`
+}
+
+type Storage struct {
+	mu sync.Mutex
+
+	employees     map[string]uuid.UUID
+	organizations map[uuid.UUID]string
+	responsibles  []responsible
+	roles         []model.RoleAssignment
+
+	tenders       map[uuid.UUID]model.Tender
+	tenderArchive map[uuid.UUID][]model.Tender
+
+	bids       map[uuid.UUID]model.Bid
+	bidArchive map[uuid.UUID][]model.Bid
+
+	feedback  []model.BidFeedback
+	decisions map[uuid.UUID][]model.BidDecision
+	ratings   []model.BidRating
+
+	audit []model.AuditEvent
+
+	notifications []model.Notification
+	subscriptions []model.Subscription
+
+	webhooks    []model.WebhookEndpoint
+	deliveries  []model.WebhookDelivery
+	deadLetters []model.WebhookDelivery
+
+	bus *notify.Bus
+	hub *notify.Hub
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		employees:     map[string]uuid.UUID{},
+		organizations: map[uuid.UUID]string{},
+		tenders:       map[uuid.UUID]model.Tender{},
+		tenderArchive: map[uuid.UUID][]model.Tender{},
+		bids:          map[uuid.UUID]model.Bid{},
+		bidArchive:    map[uuid.UUID][]model.Bid{},
+		decisions:     map[uuid.UUID][]model.BidDecision{},
+		hub:           notify.NewHub(),
+	}
+}
+
+func (s *Storage) Seed(path string) error {
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	seed := struct {
+		Employees     map[string]uuid.UUID   `json:"employees"`
+		Organizations map[uuid.UUID]string   `json:"organizations"`
+		Responsibles  []responsible          `json:"responsibles"`
+		Roles         []model.RoleAssignment `json:"roles"`
+	}{}
+
+	if err := json.Unmarshal(file, &seed); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for username, id := range seed.Employees {
+		s.employees[username] = id
+	}
+	for id, name := range seed.Organizations {
+		s.organizations[id] = name
+	}
+	roles := map[responsible][]model.Role{}
+	for _, r := range seed.Roles {
+		if !r.Role.Validate() {
+			return ErrIncorrectSeed
+		}
+		member := responsible{OrganizationId: r.OrganizationId, UserId: r.UserId}
+		roles[member] = append(roles[member], r.Role)
+	}
+	for _, r := range seed.Responsibles {
+		s.addResponsible(r.OrganizationId, r.UserId, roles[r]...)
+		delete(roles, r)
+	}
+	if len(roles) != 0 {
+		return ErrIncorrectSeed
+	}
+
+	return nil
+
+}
+
+func (s *Storage) AddEmployee(username string) uuid.UUID {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.Must(uuid.NewV4())
+	s.employees[username] = id
+
+	return id
+
+}
+
+func (s *Storage) AddOrganization(name string) uuid.UUID {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.Must(uuid.NewV4())
+	s.organizations[id] = name
+
+	return id
+
+}
+
+func (s *Storage) AddResponsible(orgId, userId uuid.UUID, roles ...model.Role) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addResponsible(orgId, userId, roles...)
+
+}
+
+func (s *Storage) addResponsible(orgId, userId uuid.UUID, roles ...model.Role) {
+
+	s.responsibles = append(s.responsibles, responsible{OrganizationId: orgId, UserId: userId})
+
+	if len(roles) == 0 {
+		roles = []model.Role{model.RoleOwner}
+		for _, r := range s.roles {
+			if r.OrganizationId == orgId && r.Role == model.RoleOwner {
+				roles = model.MemberRoles
+				break
+			}
+		}
+	}
+
+	for _, role := range roles {
+		s.grantRole(orgId, userId, role)
+	}
+
+}
+
+func (s *Storage) SetBus(bus *notify.Bus) {
+	s.bus = bus
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (s *Storage) Close() {}
+
+func (s *Storage) Employee(ctx context.Context, username string) (model.Employee, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.userId(username)
+	if err != nil {
+		return model.Employee{}, err
+	}
+
+	return model.Employee{Id: id, Username: username}, nil
+
+}
+
+func (s *Storage) userId(username string) (uuid.UUID, error) {
+
+	id, ok := s.employees[username]
+	if !ok {
+		return uuid.UUID{}, storage.ErrIncorrectUser
+	}
+
+	return id, nil
+
+}
+
+func (s *Storage) userOrgId(userId uuid.UUID) (uuid.UUID, error) {
+
+	for _, r := range s.responsibles {
+		if r.UserId == userId {
+			return r.OrganizationId, nil
+		}
+	}
+
+	return uuid.UUID{}, storage.ErrOrganizationNotFound
+
+}
+
+func (s *Storage) checkUser(id uuid.UUID) error {
+
+	for _, userId := range s.employees {
+		if userId == id {
+			return nil
+		}
+	}
+
+	return storage.ErrIncorrectUser
+
+}
+
+func (s *Storage) checkRelationToOrganization(userId, orgId uuid.UUID) bool {
+
+	for _, r := range s.responsibles {
+		if r.UserId == userId && r.OrganizationId == orgId {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func (s *Storage) checkPermission(userId, orgId uuid.UUID, action model.Action) bool {
+
+	if !s.checkRelationToOrganization(userId, orgId) {
+		return false
+	}
+
+	for _, r := range s.roles {
+		if r.UserId == userId && r.OrganizationId == orgId && r.Role.Can(action) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func (s *Storage) voters(orgId uuid.UUID) []model.Employee {
+
+	voters := []model.Employee{}
+	for username, id := range s.employees {
+		for _, r := range s.roles {
+			if r.UserId == id && r.OrganizationId == orgId && r.Role.Can(model.ActionDecideBid) && s.checkRelationToOrganization(id, orgId) {
+				voters = append(voters, model.Employee{Id: id, Username: username})
+				break
+			}
+		}
+	}
+
+	slices.SortFunc(voters, func(a, b model.Employee) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	return voters
+
+}
+
+func page[T any](items []T, limit, offset int) []T {
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+
+}
+
+func list[T model.Listable](items []T, opts model.ListOptions) model.Page[T] {
+
+	slices.SortFunc(items, func(a, b T) int {
+		return compareCursors(a.Cursor(opts.Sort), b.Cursor(opts.Sort))
+	})
+
+	total := len(items)
+
+	offset := opts.Offset
+	if opts.After != nil {
+		offset = 0
+		items = slices.DeleteFunc(items, func(item T) bool {
+			return compareCursors(item.Cursor(opts.Sort), *opts.After) <= 0
+		})
+	}
+
+	res := model.NewPage(page(items, opts.Limit+1, offset), opts)
+	if opts.Total {
+		res.Total = &total
+	}
+
+	return res
+
+}
+
+func compareCursors(a, b model.Cursor) int {
+
+	if c := compareKeys(a.Key, b.Key, a.Sort.Desc()); c != 0 {
+		return c
+	}
+
+	return bytes.Compare(a.Id.Bytes(), b.Id.Bytes())
+
+}
+
+func compareKeys(a, b any, desc bool) int {
+
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	c := 0
+	switch a := a.(type) {
+	case string:
+		c = strings.Compare(a, b.(string))
+	case time.Time:
+		c = a.Compare(b.(time.Time))
+	case model.Money:
+		c = cmp.Compare(a, b.(model.Money))
+	case float32:
+		c = cmp.Compare(a, b.(float32))
+	}
+
+	if desc {
+		return -c
+	}
+
+	return c
+
+}
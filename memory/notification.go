@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) NotificationRecipients(ctx context.Context, e model.AuditEvent) ([]uuid.UUID, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipients := []uuid.UUID{}
+	add := func(ids ...uuid.UUID) {
+		for _, id := range ids {
+			if (e.ActorId == nil || *e.ActorId != id) && !slices.Contains(recipients, id) {
+				recipients = append(recipients, id)
+			}
+		}
+	}
+
+	switch e.EntityType {
+	case model.EntityTender:
+		add(s.members(e.OrganizationId)...)
+		for _, b := range s.bids {
+			if b.TenderId == e.EntityId && b.Status != model.BidStatusCreated {
+				add(b.AuthorId)
+			}
+		}
+	case model.EntityBid:
+		bid, err := s.bid(e.EntityId)
+		if err != nil {
+			return nil, err
+		}
+		if e.VisibleTo(model.FeedbackSideAuthor) {
+			add(bid.AuthorId)
+			if orgId, err := s.userOrgId(bid.AuthorId); err == nil && bid.AuthorType == model.BidAuthorTypeOrganization {
+				add(s.members(orgId)...)
+			}
+		}
+		if tender, err := s.tender(bid.TenderId); err == nil && bid.Status != model.BidStatusCreated &&
+			e.VisibleTo(model.FeedbackSideReviewer) {
+			add(s.members(tender.OrganizationId)...)
+		}
+	default:
+		if s.checkUser(e.EntityId) == nil {
+			add(e.EntityId)
+		}
+	}
+
+	return recipients, nil
+
+}
+
+func (s *Storage) members(orgId uuid.UUID) []uuid.UUID {
+
+	members := []uuid.UUID{}
+	for _, r := range s.responsibles {
+		if r.OrganizationId == orgId {
+			members = append(members, r.UserId)
+		}
+	}
+
+	return members
+
+}
+
+func (s *Storage) UserSubscriptions(ctx context.Context, userId uuid.UUID) ([]model.Subscription, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.userSubscriptions(userId), nil
+
+}
+
+func (s *Storage) userSubscriptions(userId uuid.UUID) []model.Subscription {
+
+	subs := []model.Subscription{}
+	for _, sub := range s.subscriptions {
+		if sub.UserId == userId {
+			subs = append(subs, sub)
+		}
+	}
+
+	return subs
+
+}
+
+func (s *Storage) AddNotification(ctx context.Context, n model.Notification) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.notifications {
+		if existing.UserId == n.UserId && existing.EventId == n.EventId {
+			return nil
+		}
+	}
+
+	s.notifications = append(s.notifications, n)
+
+	return nil
+
+}
+
+func (s *Storage) ReadNotifications(ctx context.Context, username string, filter model.NotificationFilter, opts model.ListOptions) (model.Page[model.Notification], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Page[model.Notification]{}, err
+	}
+
+	inbox := []model.Notification{}
+	for _, n := range s.notifications {
+		if n.UserId == userId && (!filter.Unread || n.ReadAt == nil) {
+			inbox = append(inbox, n)
+		}
+	}
+
+	return list(inbox, opts), nil
+
+}
+
+func (s *Storage) MarkNotificationRead(ctx context.Context, username string, notificationId uuid.UUID) (model.Notification, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	for i, n := range s.notifications {
+		if n.Id == notificationId && n.UserId == userId {
+			if n.ReadAt == nil {
+				now := time.Now().UTC()
+				s.notifications[i].ReadAt = &now
+			}
+			return s.notifications[i], nil
+		}
+	}
+
+	return model.Notification{}, storage.ErrNotificationNotFound
+
+}
+
+func (s *Storage) MarkNotificationsRead(ctx context.Context, username string) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return 0, err
+	}
+
+	marked := 0
+	now := time.Now().UTC()
+	for i, n := range s.notifications {
+		if n.UserId == userId && n.ReadAt == nil {
+			s.notifications[i].ReadAt = &now
+			marked++
+		}
+	}
+
+	return marked, nil
+
+}
+
+func (s *Storage) ReadSubscriptions(ctx context.Context, username string) ([]model.Subscription, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.EffectiveSubscriptions(userId, s.userSubscriptions(userId)), nil
+
+}
+
+func (s *Storage) UpdateSubscription(ctx context.Context, username string, sub model.Subscription) (model.Subscription, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+
+	now := time.Now().UTC()
+	sub.UserId, sub.UpdatedAt = userId, &now
+	if sub.Actions == nil {
+		sub.Actions = []model.AuditAction{}
+	}
+
+	s.subscriptions = slices.DeleteFunc(s.subscriptions, func(existing model.Subscription) bool {
+		return existing.UserId == userId && existing.Channel == sub.Channel
+	})
+	s.subscriptions = append(s.subscriptions, sub)
+
+	return sub, nil
+
+}
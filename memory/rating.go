@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"time"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) RateBid(ctx context.Context, bidId uuid.UUID, username string, rating model.BidRating) (model.BidRating, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.BidRating{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return model.BidRating{}, err
+	}
+
+	tender, err := s.tender(bid.TenderId)
+	if err != nil {
+		return model.BidRating{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionFeedback) {
+		return model.BidRating{}, storage.ErrNotEnoughPerm
+	}
+
+	if bid.Status != model.BidStatusApproved || tender.Status != model.TenderStatusClosed {
+		return model.BidRating{}, storage.ErrBidNotRatable
+	}
+
+	for _, r := range s.ratings {
+		if r.BidId == bidId {
+			return model.BidRating{}, storage.ErrBidRated
+		}
+	}
+
+	var authorOrgId *uuid.UUID
+	if orgId, err := s.userOrgId(bid.AuthorId); err == nil {
+		authorOrgId = &orgId
+	}
+
+	rated := model.BidRating{
+		Id:                   uuid.Must(uuid.NewV4()),
+		BidId:                bidId,
+		TenderId:             tender.Id,
+		AuthorId:             bid.AuthorId,
+		AuthorOrganizationId: authorOrgId,
+		UserId:               &userId,
+		OrganizationId:       tender.OrganizationId,
+		Quality:              rating.Quality,
+		Timeliness:           rating.Timeliness,
+		Price:                rating.Price,
+		Comment:              rating.Comment,
+		CreatedAt:            time.Now().UTC(),
+	}
+	s.ratings = append(s.ratings, rated)
+	s.record(ctx, model.BidEvent(model.AuditBidRate, &userId, tender.OrganizationId, &bid, bid))
+
+	return rated, nil
+
+}
+
+func (s *Storage) filterRatings(username string, filter model.ReputationFilter) ([]model.BidRating, error) {
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return nil, err
+	}
+
+	orgId, err := s.userOrgId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.checkPermission(userId, orgId, model.ActionView) {
+		return nil, storage.ErrNotEnoughPerm
+	}
+
+	match := func(r model.BidRating) bool {
+		return r.AuthorOrganizationId != nil && *r.AuthorOrganizationId == *filter.OrganizationId
+	}
+	if filter.OrganizationId == nil {
+		authorId, err := s.userId(filter.Author)
+		if err != nil {
+			return nil, err
+		}
+		match = func(r model.BidRating) bool {
+			return r.AuthorId == authorId
+		}
+	}
+
+	ratings := []model.BidRating{}
+	for _, r := range s.ratings {
+		if match(r) {
+			ratings = append(ratings, r)
+		}
+	}
+
+	return ratings, nil
+
+}
+
+func (s *Storage) ReadReputation(ctx context.Context, username string, filter model.ReputationFilter) (model.Reputation, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ratings, err := s.filterRatings(username, filter)
+	if err != nil {
+		return model.Reputation{}, err
+	}
+
+	return model.NewReputation(ratings), nil
+
+}
+
+func (s *Storage) ReadRatings(ctx context.Context, username string, filter model.ReputationFilter, opts model.ListOptions) (model.Page[model.BidRating], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ratings, err := s.filterRatings(username, filter)
+	if err != nil {
+		return model.Page[model.BidRating]{}, err
+	}
+
+	return list(ratings, opts), nil
+
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) username(userId uuid.UUID) string {
+
+	for username, id := range s.employees {
+		if id == userId {
+			return username
+		}
+	}
+
+	return ""
+
+}
+
+func (s *Storage) grantRole(orgId, userId uuid.UUID, role model.Role) (model.RoleAssignment, bool) {
+
+	for _, r := range s.roles {
+		if r.OrganizationId == orgId && r.UserId == userId && r.Role == role {
+			return r, false
+		}
+	}
+
+	r := model.RoleAssignment{
+		OrganizationId: orgId,
+		UserId:         userId,
+		Username:       s.username(userId),
+		Role:           role,
+		CreatedAt:      time.Now().UTC(),
+	}
+	s.roles = append(s.roles, r)
+
+	return r, true
+
+}
+
+func (s *Storage) ReadRoles(ctx context.Context, orgId uuid.UUID, username string) ([]model.RoleAssignment, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.checkPermission(userId, orgId, model.ActionView) {
+		return nil, storage.ErrNotEnoughPerm
+	}
+
+	roles := []model.RoleAssignment{}
+	for _, r := range s.roles {
+		if r.OrganizationId == orgId {
+			roles = append(roles, r)
+		}
+	}
+
+	slices.SortFunc(roles, func(a, b model.RoleAssignment) int {
+		if c := strings.Compare(a.Username, b.Username); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Role), string(b.Role))
+	})
+
+	return roles, nil
+
+}
+
+func (s *Storage) GrantRole(ctx context.Context, orgId uuid.UUID, username string, target string, role model.Role) (model.RoleAssignment, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.RoleAssignment{}, err
+	}
+
+	if !s.checkPermission(userId, orgId, model.ActionManageRoles) {
+		return model.RoleAssignment{}, storage.ErrNotEnoughPerm
+	}
+
+	targetId, err := s.userId(target)
+	if err != nil {
+		return model.RoleAssignment{}, err
+	}
+
+	if !s.checkRelationToOrganization(targetId, orgId) {
+		return model.RoleAssignment{}, storage.ErrNotMember
+	}
+
+	assignment, created := s.grantRole(orgId, targetId, role)
+	if created {
+		s.record(ctx, model.RoleEvent(model.AuditRoleGrant, userId, orgId, targetId))
+	}
+
+	return assignment, nil
+
+}
+
+func (s *Storage) RevokeRole(ctx context.Context, orgId uuid.UUID, username string, target string, role model.Role) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return err
+	}
+
+	if !s.checkPermission(userId, orgId, model.ActionManageRoles) {
+		return storage.ErrNotEnoughPerm
+	}
+
+	targetId, err := s.userId(target)
+	if err != nil {
+		return err
+	}
+
+	idx, owners := -1, 0
+	for i, r := range s.roles {
+		if r.OrganizationId != orgId {
+			continue
+		}
+		if r.Role == model.RoleOwner {
+			owners++
+		}
+		if r.UserId == targetId && r.Role == role {
+			idx = i
+		}
+	}
+
+	if idx < 0 {
+		return storage.ErrRoleNotFound
+	}
+
+	if role == model.RoleOwner && owners <= 1 {
+		return storage.ErrLastOwner
+	}
+
+	s.roles = slices.Delete(s.roles, idx, idx+1)
+	s.record(ctx, model.RoleEvent(model.AuditRoleRevoke, userId, orgId, targetId))
+
+	return nil
+
+}
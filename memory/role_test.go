@@ -0,0 +1,60 @@
+package memory_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"zadanie/memory"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestDefaultRoles(t *testing.T) {
+
+	s := memory.NewStorage()
+
+	org := s.AddOrganization("org")
+	s.AddResponsible(org, s.AddEmployee("owner"))
+	s.AddResponsible(org, s.AddEmployee("member"))
+	s.AddResponsible(org, s.AddEmployee("viewer"), model.RoleViewer)
+
+	want := map[string][]model.Role{
+		"owner":  {model.RoleOwner},
+		"member": {model.RoleApprover, model.RoleEditor},
+		"viewer": {model.RoleViewer},
+	}
+	if got := roles(t, s, org); !reflect.DeepEqual(got, want) {
+		t.Fatalf("roles %v, want %v", got, want)
+	}
+
+	if _, err := s.CreateTender(context.Background(), model.Tender{
+		Name:           "t",
+		Description:    "d",
+		ServiceType:    model.TenderServiceTypeDelivery,
+		OrganizationId: org,
+	}, "member"); err != nil {
+		t.Fatalf("member cannot create tender: %v", err)
+	}
+
+}
+
+func roles(t *testing.T, s *memory.Storage, orgId uuid.UUID) map[string][]model.Role {
+
+	assignments, err := s.ReadRoles(context.Background(), orgId, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roles := map[string][]model.Role{}
+	for _, a := range assignments {
+		roles[a.Username] = append(roles[a.Username], a.Role)
+	}
+	for _, r := range roles {
+		slices.Sort(r)
+	}
+
+	return roles
+
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) publishChange(e model.AuditEvent) {
+
+	c, ok := e.TenderChange()
+	if !ok {
+		return
+	}
+
+	if c.EntityType == model.EntityBid {
+		bid, ok := e.Subject.(model.Bid)
+		if !ok {
+			var err error
+			if bid, err = s.bid(e.EntityId); err != nil {
+				return
+			}
+		}
+		if tender, err := s.tender(c.TenderId); err == nil {
+			c.TenderOrganizationId = tender.OrganizationId
+		}
+		if orgId, err := s.userOrgId(bid.AuthorId); err == nil {
+			c.BidOrganizationId = &orgId
+		}
+		if len(c.BidStatus) == 0 {
+			c.BidStatus = bid.Status
+		}
+	}
+
+	s.hub.Publish(c)
+
+}
+
+func (s *Storage) ListenChanges(ctx context.Context) {}
+
+func (s *Storage) WatchTender(ctx context.Context, tenderId uuid.UUID, username string) (<-chan model.TenderChange, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return nil, err
+	}
+
+	orgId, err := s.userOrgId(userId)
+	if err != nil {
+		orgId = uuid.Nil
+	}
+
+	if tender.Status != model.TenderStatusPublished && !s.checkPermission(userId, tender.OrganizationId, model.ActionView) {
+		return nil, storage.ErrNotEnoughPerm
+	}
+
+	return s.hub.Watch(ctx, tenderId, orgId), nil
+
+}
@@ -0,0 +1,520 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) tender(id uuid.UUID) (model.Tender, error) {
+
+	tender, ok := s.tenders[id]
+	if !ok {
+		return model.Tender{}, storage.ErrTenderNotFound
+	}
+
+	return tender, nil
+
+}
+
+func (s *Storage) tenderVer(id uuid.UUID, ver int) (model.Tender, error) {
+
+	for _, tender := range s.tenderArchive[id] {
+		if int(tender.Version) == ver {
+			return tender, nil
+		}
+	}
+
+	return model.Tender{}, storage.ErrVersionNotFound
+
+}
+
+func (s *Storage) saveTender(tender model.Tender) model.Tender {
+
+	s.tenders[tender.Id] = tender
+	s.tenderArchive[tender.Id] = append(s.tenderArchive[tender.Id], tender)
+
+	return tender
+
+}
+
+func (s *Storage) bumpTender(tender model.Tender, by *uuid.UUID, op model.Operation) model.Tender {
+
+	tender.ModifiedBy = by
+	tender.Operation = op
+	tender.Version++
+	tender.UpdatedAt = time.Now().UTC()
+
+	return s.saveTender(tender)
+
+}
+
+func (s *Storage) CreateTender(ctx context.Context, tender model.Tender, username string) (model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionCreateTender) {
+		return model.Tender{}, storage.ErrNotEnoughPerm
+	}
+
+	now := time.Now().UTC()
+
+	if !model.ValidateDeadlines(tender, tender, now) {
+		return model.Tender{}, storage.ErrIncorrectDeadline
+	}
+
+	if !tender.ValidateBudget() {
+		return model.Tender{}, storage.ErrIncorrectBudget
+	}
+
+	if !tender.ValidatePolicy() {
+		return model.Tender{}, storage.ErrIncorrectPolicy
+	}
+
+	if len(tender.BudgetPolicy) == 0 {
+		tender.BudgetPolicy = model.BudgetPolicyWarn
+	}
+
+	if len(tender.ApprovalRule) == 0 {
+		tender.ApprovalRule = model.ApprovalRuleQuorum
+	}
+
+	if len(tender.RejectionRule) == 0 {
+		tender.RejectionRule = model.RejectionRuleFirstVeto
+	}
+
+	created := s.saveTender(model.Tender{
+		Id:                 uuid.Must(uuid.NewV4()),
+		Name:               tender.Name,
+		Description:        tender.Description,
+		ServiceType:        tender.ServiceType,
+		Status:             model.TenderStatusCreated,
+		OrganizationId:     tender.OrganizationId,
+		Version:            1,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		SubmissionDeadline: utc(tender.SubmissionDeadline),
+		DecisionDeadline:   utc(tender.DecisionDeadline),
+		BudgetMin:          tender.BudgetMin,
+		BudgetMax:          tender.BudgetMax,
+		Currency:           tender.Currency,
+		BudgetPolicy:       tender.BudgetPolicy,
+		ApprovalRule:       tender.ApprovalRule,
+		ApprovalThreshold:  tender.ApprovalThreshold,
+		RejectionRule:      tender.RejectionRule,
+		MaxAwards:          tender.MaxAwards,
+		ModifiedBy:         &userId,
+		Operation:          model.OperationCreate,
+	})
+	s.record(ctx, model.TenderEvent(model.AuditTenderCreate, &userId, nil, created))
+
+	return created, nil
+
+}
+
+func (s *Storage) ReadTenders(ctx context.Context, opts model.ListOptions, types []model.TenderServiceType) (model.Page[model.Tender], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenders := []model.Tender{}
+	for _, tender := range s.tenders {
+		if tender.Status != model.TenderStatusPublished {
+			continue
+		}
+		if len(types) != 0 && !slices.Contains(types, tender.ServiceType) {
+			continue
+		}
+		tenders = append(tenders, tender)
+	}
+
+	return list(tenders, opts), nil
+
+}
+
+func matchTender(tender model.Tender, terms []string) (model.TenderMatch, bool) {
+
+	name, description := strings.ToLower(tender.Name), strings.ToLower(tender.Description)
+
+	rank := float32(0)
+	for _, term := range terms {
+		n, d := strings.Count(name, term), strings.Count(description, term)
+		if n+d == 0 {
+			return model.TenderMatch{}, false
+		}
+		rank += float32(2*n + d)
+	}
+
+	return model.TenderMatch{
+		Tender:  tender,
+		Rank:    rank,
+		Snippet: highlight(tender.Name+" — "+tender.Description, terms),
+	}, true
+
+}
+
+func highlight(text string, terms []string) string {
+
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return text
+	}
+
+	b := strings.Builder{}
+	for i := 0; i < len(text); {
+		matched := ""
+		for _, term := range terms {
+			if strings.HasPrefix(lower[i:], term) && len(term) > len(matched) {
+				matched = term
+			}
+		}
+		if len(matched) == 0 {
+			b.WriteByte(text[i])
+			i++
+			continue
+		}
+		b.WriteString("<b>" + text[i:i+len(matched)] + "</b>")
+		i += len(matched)
+	}
+
+	return b.String()
+
+}
+
+func (s *Storage) SearchTenders(ctx context.Context, search string, opts model.ListOptions, types []model.TenderServiceType) (model.Page[model.TenderMatch], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	terms := strings.Fields(strings.ToLower(search))
+
+	matches := []model.TenderMatch{}
+	for _, tender := range s.tenders {
+		if tender.Status != model.TenderStatusPublished {
+			continue
+		}
+		if len(types) != 0 && !slices.Contains(types, tender.ServiceType) {
+			continue
+		}
+		if match, ok := matchTender(tender, terms); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	return list(matches, opts), nil
+
+}
+
+func (s *Storage) ReadMyTenders(ctx context.Context, username string, opts model.ListOptions) (model.Page[model.Tender], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Page[model.Tender]{}, err
+	}
+
+	orgId, err := s.userOrgId(userId)
+	if err != nil {
+		return model.Page[model.Tender]{}, err
+	}
+
+	tenders := []model.Tender{}
+	for _, tender := range s.tenders {
+		if tender.OrganizationId == orgId {
+			tenders = append(tenders, tender)
+		}
+	}
+
+	return list(tenders, opts), nil
+
+}
+
+func (s *Storage) ReadTender(ctx context.Context, tenderId uuid.UUID, username string) (model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	if tender.Status == model.TenderStatusPublished {
+		return tender, nil
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionView) {
+		return model.Tender{}, storage.ErrNotEnoughPerm
+	}
+
+	return tender, nil
+
+}
+
+func (s *Storage) ReadTenderVersions(ctx context.Context, tenderId uuid.UUID, username string) ([]model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionView) {
+		return nil, storage.ErrNotEnoughPerm
+	}
+
+	return slices.Clone(s.tenderArchive[tenderId]), nil
+
+}
+
+func (s *Storage) ReadTenderVersion(ctx context.Context, tenderId uuid.UUID, username string, ver int) (model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionView) {
+		return model.Tender{}, storage.ErrNotEnoughPerm
+	}
+
+	return s.tenderVer(tenderId, ver)
+
+}
+
+func (s *Storage) UpdateTenderStatus(ctx context.Context, tenderId uuid.UUID, username string, status model.TenderStatus, match model.Versions) (model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionPublishTender) {
+		return model.Tender{}, storage.ErrNotEnoughPerm
+	}
+
+	if !match.Match(int(tender.Version)) {
+		return model.Tender{}, storage.ErrVersionMismatch
+	}
+
+	if tender.Status == model.TenderStatusClosed {
+		return model.Tender{}, storage.ErrTenderClosed
+	}
+
+	tender.Status = status
+	tender.CloseReason = nil
+
+	updated := s.bumpTender(tender, &userId, model.OperationStatusChange)
+	s.record(ctx, model.TenderEvent(model.AuditTenderStatus, &userId, &tender, updated))
+
+	if updated.Status == model.TenderStatusClosed {
+		s.rejectOpenBids(ctx, updated, &userId)
+	}
+
+	return updated, nil
+
+}
+
+func (s *Storage) UpdateTender(ctx context.Context, tenderId uuid.UUID, username string, new model.Tender, match model.Versions) (model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionEditTender) {
+		return model.Tender{}, storage.ErrNotEnoughPerm
+	}
+
+	if !match.Match(int(tender.Version)) {
+		return model.Tender{}, storage.ErrVersionMismatch
+	}
+
+	if tender.Status == model.TenderStatusClosed {
+		return model.Tender{}, storage.ErrTenderClosed
+	}
+
+	if len(new.Name) != 0 {
+		tender.Name = new.Name
+	}
+	if len(new.Description) != 0 {
+		tender.Description = new.Description
+	}
+	if len(new.ServiceType) != 0 {
+		tender.ServiceType = new.ServiceType
+	}
+	if new.SubmissionDeadline != nil {
+		tender.SubmissionDeadline = utc(new.SubmissionDeadline)
+	}
+	if new.DecisionDeadline != nil {
+		tender.DecisionDeadline = utc(new.DecisionDeadline)
+	}
+	if new.BudgetMin != nil {
+		tender.BudgetMin = new.BudgetMin
+	}
+	if new.BudgetMax != nil {
+		tender.BudgetMax = new.BudgetMax
+	}
+	if new.Currency != nil {
+		tender.Currency = new.Currency
+	}
+	if len(new.BudgetPolicy) != 0 {
+		tender.BudgetPolicy = new.BudgetPolicy
+	}
+
+	if !model.ValidateDeadlines(tender, new, time.Now()) {
+		return model.Tender{}, storage.ErrIncorrectDeadline
+	}
+
+	if !tender.ValidateBudget() {
+		return model.Tender{}, storage.ErrIncorrectBudget
+	}
+
+	if !tender.ValidatePolicy() {
+		return model.Tender{}, storage.ErrIncorrectPolicy
+	}
+
+	updated := s.bumpTender(tender, &userId, model.OperationEdit)
+	s.record(ctx, model.TenderEvent(model.AuditTenderEdit, &userId, &tender, updated))
+
+	return updated, nil
+
+}
+
+func (s *Storage) RollbackTender(ctx context.Context, tenderId uuid.UUID, username string, ver int, match model.Versions) (model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tender, err := s.tender(tenderId)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	if !s.checkPermission(userId, tender.OrganizationId, model.ActionEditTender) {
+		return model.Tender{}, storage.ErrNotEnoughPerm
+	}
+
+	if !match.Match(int(tender.Version)) {
+		return model.Tender{}, storage.ErrVersionMismatch
+	}
+
+	oldTender, err := s.tenderVer(tenderId, ver)
+	if err != nil {
+		return model.Tender{}, err
+	}
+
+	closing := tender.Status != model.TenderStatusClosed && oldTender.Status == model.TenderStatusClosed
+
+	tender.Name = oldTender.Name
+	tender.Description = oldTender.Description
+	tender.ServiceType = oldTender.ServiceType
+	tender.Status = oldTender.Status
+	tender.SubmissionDeadline = oldTender.SubmissionDeadline
+	tender.DecisionDeadline = oldTender.DecisionDeadline
+	tender.CloseReason = oldTender.CloseReason
+	tender.BudgetMin = oldTender.BudgetMin
+	tender.BudgetMax = oldTender.BudgetMax
+	tender.Currency = oldTender.Currency
+	tender.BudgetPolicy = oldTender.BudgetPolicy
+
+	updated := s.bumpTender(tender, &userId, model.OperationRollback)
+	s.record(ctx, model.TenderEvent(model.AuditTenderRollback, &userId, &tender, updated))
+
+	if closing {
+		s.rejectOpenBids(ctx, updated, &userId)
+	}
+
+	return updated, nil
+
+}
+
+func (s *Storage) CloseExpiredTenders(ctx context.Context, now time.Time) ([]model.Tender, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	closed := []model.Tender{}
+	for _, tender := range s.tenders {
+		reason, ok := tender.Expired(now)
+		if !ok {
+			continue
+		}
+
+		tender.Status = model.TenderStatusClosed
+		tender.CloseReason = &reason
+		updated := s.bumpTender(tender, nil, model.OperationClose)
+		s.record(ctx, model.TenderEvent(model.AuditTenderClose, nil, &tender, updated))
+		s.rejectOpenBids(ctx, updated, nil)
+		closed = append(closed, updated)
+	}
+
+	return closed, nil
+
+}
+
+func utc(t *time.Time) *time.Time {
+
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+
+	return &u
+
+}
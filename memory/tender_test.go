@@ -0,0 +1,152 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"zadanie/memory"
+	"zadanie/model"
+	"zadanie/storage"
+)
+
+var hostile = []string{
+	`O'Brien`,
+	`'; DROP TABLE tender; --`,
+	`%_\`,
+	`" OR 1=1 --`,
+	`$1`,
+	`Доставка «срочно» 🚚`,
+	"\u200b\ufeff\u202e",
+}
+
+func TestHostileValuesRoundTrip(t *testing.T) {
+
+	ctx := context.Background()
+	s := memory.NewStorage()
+
+	org := s.AddOrganization("org")
+	s.AddResponsible(org, s.AddEmployee("owner"))
+	authorOrg := s.AddOrganization("author org")
+	author := s.AddEmployee("author")
+	s.AddResponsible(authorOrg, author)
+
+	opts := model.ListOptions{Sort: "-updated_at", Limit: 50}
+
+	for _, value := range hostile {
+		t.Run(value, func(t *testing.T) {
+
+			tender, err := s.CreateTender(ctx, model.Tender{
+				Name:           "t",
+				Description:    "d",
+				ServiceType:    model.TenderServiceTypeDelivery,
+				OrganizationId: org,
+			}, "owner")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.UpdateTenderStatus(ctx, tender.Id, "owner", model.TenderStatusPublished, nil); err != nil {
+				t.Fatal(err)
+			}
+			if tender, err = s.UpdateTender(ctx, tender.Id, "owner", model.Tender{Name: value, Description: value}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if tender.Name != value || tender.Description != value {
+				t.Fatalf("updated tender %q, %q", tender.Name, tender.Description)
+			}
+
+			page, err := s.ReadTenders(ctx, opts, []model.TenderServiceType{model.TenderServiceTypeDelivery})
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, got := range page.Items {
+				if got.Id == tender.Id {
+					found = got.Name == value && got.Description == value
+				}
+			}
+			if !found {
+				t.Fatalf("tender %q not read back unchanged", value)
+			}
+
+			page, err = s.ReadTenders(ctx, opts, []model.TenderServiceType{model.TenderServiceType(value)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Items) != 0 {
+				t.Fatalf("service type %q matched %d tenders", value, len(page.Items))
+			}
+
+			bid, err := s.CreateBid(ctx, model.Bid{
+				Name:        "b",
+				Description: "d",
+				TenderId:    tender.Id,
+				AuthorType:  model.BidAuthorTypeUser,
+				AuthorId:    author,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bid, err = s.UpdateBid(ctx, bid.Id, "author", model.Bid{Name: value, Description: value}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if bid.Name != value || bid.Description != value {
+				t.Fatalf("updated bid %q, %q", bid.Name, bid.Description)
+			}
+
+			bids, err := s.ReadMyBids(ctx, "author", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found = false
+			for _, got := range bids.Items {
+				if got.Id == bid.Id {
+					found = got.Name == value && got.Description == value
+				}
+			}
+			if !found {
+				t.Fatalf("bid %q not read back unchanged", value)
+			}
+
+		})
+	}
+
+}
+
+func TestIncorrectPolicy(t *testing.T) {
+
+	ctx := context.Background()
+	s := memory.NewStorage()
+
+	org := s.AddOrganization("org")
+	s.AddResponsible(org, s.AddEmployee("owner"))
+
+	zero, hundred := 0, 101
+	tests := []struct {
+		name      string
+		rule      model.ApprovalRule
+		threshold *int
+	}{
+		{"fixed without threshold", model.ApprovalRuleFixed, nil},
+		{"percent without threshold", model.ApprovalRulePercent, nil},
+		{"fixed zero", model.ApprovalRuleFixed, &zero},
+		{"percent over hundred", model.ApprovalRulePercent, &hundred},
+		{"quorum with threshold", model.ApprovalRuleQuorum, &zero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateTender(ctx, model.Tender{
+				Name:              "t",
+				Description:       "d",
+				ServiceType:       model.TenderServiceTypeDelivery,
+				OrganizationId:    org,
+				ApprovalRule:      tt.rule,
+				ApprovalThreshold: tt.threshold,
+			}, "owner")
+			if !errors.Is(err, storage.ErrIncorrectPolicy) {
+				t.Fatalf("error %v, want %v", err, storage.ErrIncorrectPolicy)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"time"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+type participant struct {
+	userId uuid.UUID
+	orgId  uuid.UUID
+	side   model.FeedbackSide
+	bid    model.Bid
+}
+
+func (s *Storage) participant(bidId uuid.UUID, username string, post bool) (participant, error) {
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return participant{}, err
+	}
+
+	bid, err := s.bid(bidId)
+	if err != nil {
+		return participant{}, err
+	}
+
+	tender, err := s.tender(bid.TenderId)
+	if err != nil {
+		return participant{}, err
+	}
+
+	bidOrgId, err := s.userOrgId(bid.AuthorId)
+	hasOrg := err == nil
+
+	authorAction, reviewerAction := model.ActionView, model.ActionView
+	if post {
+		authorAction, reviewerAction = model.ActionEditBid, model.ActionFeedback
+	}
+
+	p := participant{userId: userId, bid: bid}
+	switch {
+	case userId == bid.AuthorId, hasOrg && s.checkPermission(userId, bidOrgId, authorAction):
+		p.side, p.orgId = model.FeedbackSideAuthor, bidOrgId
+	case bid.Status == model.BidStatusCreated || bid.Status == model.BidStatusCanceled:
+		return participant{}, storage.ErrNotEnoughPerm
+	case s.checkPermission(userId, tender.OrganizationId, reviewerAction):
+		p.side, p.orgId = model.FeedbackSideReviewer, tender.OrganizationId
+	default:
+		return participant{}, storage.ErrNotEnoughPerm
+	}
+
+	return p, nil
+
+}
+
+func (s *Storage) message(p participant, messageId uuid.UUID) (int, error) {
+
+	for i, f := range s.feedback {
+		if f.Id == messageId && f.BidId == p.bid.Id && f.VisibleTo(p.side) {
+			return i, nil
+		}
+	}
+
+	return 0, storage.ErrMessageNotFound
+
+}
+
+func (s *Storage) ownMessage(p participant, messageId uuid.UUID) (int, error) {
+
+	i, err := s.message(p, messageId)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.feedback[i].UserId == nil || *s.feedback[i].UserId != p.userId {
+		return 0, storage.ErrNotEnoughPerm
+	}
+
+	if !s.feedback[i].Editable(time.Now().UTC()) {
+		return 0, storage.ErrMessageLocked
+	}
+
+	return i, nil
+
+}
+
+func (s *Storage) ReadBidThread(ctx context.Context, bidId uuid.UUID, username string, opts model.ListOptions) (model.Page[model.BidFeedback], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.participant(bidId, username, false)
+	if err != nil {
+		return model.Page[model.BidFeedback]{}, err
+	}
+
+	thread := []model.BidFeedback{}
+	for _, f := range s.feedback {
+		if f.BidId == bidId && f.VisibleTo(p.side) {
+			thread = append(thread, f)
+		}
+	}
+
+	return list(thread, opts), nil
+
+}
+
+func (s *Storage) PostBidMessage(ctx context.Context, bidId uuid.UUID, username string, message model.BidFeedback) (model.BidFeedback, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.participant(bidId, username, true)
+	if err != nil {
+		return model.BidFeedback{}, err
+	}
+
+	if message.ParentId != nil {
+		if _, err := s.message(p, *message.ParentId); err != nil {
+			return model.BidFeedback{}, err
+		}
+	}
+
+	if len(message.Visibility) == 0 {
+		message.Visibility = model.FeedbackVisibilityPublic
+	}
+
+	posted := model.BidFeedback{
+		Id:          uuid.Must(uuid.NewV4()),
+		TenderId:    p.bid.TenderId,
+		BidId:       bidId,
+		UserId:      &p.userId,
+		Description: message.Description,
+		CreatedAt:   time.Now().UTC(),
+		ParentId:    message.ParentId,
+		Side:        p.side,
+		Visibility:  message.Visibility,
+	}
+	s.feedback = append(s.feedback, posted)
+	s.record(ctx, model.BidEvent(model.AuditBidMessage, &p.userId, p.orgId, &p.bid, p.bid).About(posted))
+
+	return posted, nil
+
+}
+
+func (s *Storage) EditBidMessage(ctx context.Context, bidId, messageId uuid.UUID, username string, text string) (model.BidFeedback, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.participant(bidId, username, true)
+	if err != nil {
+		return model.BidFeedback{}, err
+	}
+
+	i, err := s.ownMessage(p, messageId)
+	if err != nil {
+		return model.BidFeedback{}, err
+	}
+
+	now := time.Now().UTC()
+	s.feedback[i].Description = text
+	s.feedback[i].UpdatedAt = &now
+	s.record(ctx, model.BidEvent(model.AuditBidMessageEdit, &p.userId, p.orgId, &p.bid, p.bid))
+
+	return s.feedback[i], nil
+
+}
+
+func (s *Storage) DeleteBidMessage(ctx context.Context, bidId, messageId uuid.UUID, username string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.participant(bidId, username, true)
+	if err != nil {
+		return err
+	}
+
+	i, err := s.ownMessage(p, messageId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	s.feedback[i].Description = ""
+	s.feedback[i].DeletedAt = &now
+	s.record(ctx, model.BidEvent(model.AuditBidMessageDrop, &p.userId, p.orgId, &p.bid, p.bid))
+
+	return nil
+
+}
@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+	"zadanie/dto"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+)
+
+func (s *Storage) enqueueWebhooks(e model.AuditEvent) {
+
+	eventType, ok := e.WebhookEvent()
+	if !ok {
+		return
+	}
+
+	orgIds := []uuid.UUID{e.OrganizationId}
+	if e.EntityType == model.EntityBid {
+		bid, err := s.bid(e.EntityId)
+		if err != nil {
+			return
+		}
+		orgIds = []uuid.UUID{}
+		if tender, err := s.tender(bid.TenderId); err == nil && bid.Status != model.BidStatusCreated {
+			orgIds = append(orgIds, tender.OrganizationId)
+		}
+		if orgId, err := s.userOrgId(bid.AuthorId); err == nil {
+			orgIds = append(orgIds, orgId)
+		}
+	}
+
+	payload, err := json.Marshal(dto.NewWebhookEvent(e, eventType))
+	if err != nil {
+		return
+	}
+
+	for _, w := range s.webhooks {
+		if !slices.Contains(orgIds, w.OrganizationId) || !w.Wants(eventType) {
+			continue
+		}
+		s.deliveries = append(s.deliveries, model.WebhookDelivery{
+			Id:             uuid.Must(uuid.NewV4()),
+			EndpointId:     w.Id,
+			OrganizationId: w.OrganizationId,
+			EventId:        e.Id,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         model.DeliveryStatusPending,
+			NextAttemptAt:  e.CreatedAt,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+
+}
+
+func (s *Storage) hookOrgId(username string) (uuid.UUID, uuid.UUID, error) {
+
+	userId, err := s.userId(username)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+
+	orgId, err := s.userOrgId(userId)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+
+	if !s.checkPermission(userId, orgId, model.ActionManageHooks) {
+		return uuid.UUID{}, uuid.UUID{}, storage.ErrNotEnoughPerm
+	}
+
+	return userId, orgId, nil
+
+}
+
+func (s *Storage) CreateWebhook(ctx context.Context, username string, endpoint model.WebhookEndpoint) (model.WebhookEndpoint, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userId, orgId, err := s.hookOrgId(username)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+
+	enabled := endpoint.Enabled == nil || *endpoint.Enabled
+
+	created := model.WebhookEndpoint{
+		Id:             uuid.Must(uuid.NewV4()),
+		OrganizationId: orgId,
+		URL:            endpoint.URL,
+		Secret:         model.NewWebhookSecret(),
+		EventTypes:     endpoint.EventTypes,
+		Enabled:        &enabled,
+		CreatedBy:      &userId,
+		CreatedAt:      time.Now().UTC(),
+	}
+	s.webhooks = append(s.webhooks, created)
+
+	return created, nil
+
+}
+
+func (s *Storage) ReadWebhooks(ctx context.Context, username string) ([]model.WebhookEndpoint, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, orgId, err := s.hookOrgId(username)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []model.WebhookEndpoint{}
+	for _, w := range s.webhooks {
+		if w.OrganizationId == orgId {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return webhooks, nil
+
+}
+
+func (s *Storage) DeleteWebhook(ctx context.Context, username string, webhookId uuid.UUID) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, orgId, err := s.hookOrgId(username)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(s.webhooks, func(w model.WebhookEndpoint) bool {
+		return w.Id == webhookId && w.OrganizationId == orgId
+	})
+	if idx < 0 {
+		return storage.ErrWebhookNotFound
+	}
+
+	s.webhooks = slices.Delete(s.webhooks, idx, idx+1)
+
+	owned := func(d model.WebhookDelivery) bool {
+		return d.EndpointId == webhookId
+	}
+	s.deliveries = slices.DeleteFunc(s.deliveries, owned)
+	s.deadLetters = slices.DeleteFunc(s.deadLetters, owned)
+
+	return nil
+
+}
+
+func (s *Storage) ReadDeliveries(ctx context.Context, username string, filter model.DeliveryFilter, opts model.ListOptions) (model.Page[model.WebhookDelivery], error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, orgId, err := s.hookOrgId(username)
+	if err != nil {
+		return model.Page[model.WebhookDelivery]{}, err
+	}
+
+	if !slices.ContainsFunc(s.webhooks, func(w model.WebhookEndpoint) bool {
+		return w.Id == filter.EndpointId && w.OrganizationId == orgId
+	}) {
+		return model.Page[model.WebhookDelivery]{}, storage.ErrWebhookNotFound
+	}
+
+	source := s.deliveries
+	if filter.Status == model.DeliveryStatusDead {
+		source = s.deadLetters
+	}
+
+	deliveries := []model.WebhookDelivery{}
+	for _, d := range source {
+		if d.EndpointId == filter.EndpointId && (len(filter.Status) == 0 || d.Status == filter.Status) {
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	return list(deliveries, opts), nil
+
+}
+
+func (s *Storage) Redeliver(ctx context.Context, username string, deliveryId uuid.UUID) (model.WebhookDelivery, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, orgId, err := s.hookOrgId(username)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	match := func(d model.WebhookDelivery) bool {
+		return d.Id == deliveryId && d.OrganizationId == orgId
+	}
+
+	if idx := slices.IndexFunc(s.deadLetters, match); idx >= 0 {
+		s.deliveries = append(s.deliveries, s.deadLetters[idx])
+		s.deadLetters = slices.Delete(s.deadLetters, idx, idx+1)
+	}
+
+	idx := slices.IndexFunc(s.deliveries, match)
+	if idx < 0 {
+		return model.WebhookDelivery{}, storage.ErrDeliveryNotFound
+	}
+
+	d := &s.deliveries[idx]
+	d.Status, d.Attempts, d.NextAttemptAt = model.DeliveryStatusPending, 0, time.Now().UTC()
+	d.LastError, d.ResponseCode, d.DeliveredAt = nil, nil, nil
+
+	return *d, nil
+
+}
+
+func (s *Storage) ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	claimed := []model.WebhookDelivery{}
+	for i, d := range s.deliveries {
+
+		if len(claimed) == limit {
+			break
+		}
+		if d.Status != model.DeliveryStatusPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+
+		idx := slices.IndexFunc(s.webhooks, func(w model.WebhookEndpoint) bool {
+			return w.Id == d.EndpointId
+		})
+		if idx < 0 {
+			continue
+		}
+
+		s.deliveries[i].NextAttemptAt = now.Add(lease)
+		d.NextAttemptAt, d.URL, d.Secret = now.Add(lease), s.webhooks[idx].URL, s.webhooks[idx].Secret
+		claimed = append(claimed, d)
+
+	}
+
+	return claimed, nil
+
+}
+
+func (s *Storage) SaveDelivery(ctx context.Context, d model.WebhookDelivery) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.deliveries, func(existing model.WebhookDelivery) bool {
+		return existing.Id == d.Id
+	})
+	if idx < 0 {
+		return storage.ErrDeliveryNotFound
+	}
+
+	d.URL, d.Secret = "", ""
+	if d.Status == model.DeliveryStatusDead {
+		s.deliveries = slices.Delete(s.deliveries, idx, idx+1)
+		s.deadLetters = append(s.deadLetters, d)
+		return nil
+	}
+
+	s.deliveries[idx] = d
+
+	return nil
+
+}
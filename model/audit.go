@@ -0,0 +1,146 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type EntityType string
+
+const (
+	EntityTender   EntityType = "Tender"
+	EntityBid      EntityType = "Bid"
+	EntityEmployee EntityType = "Employee"
+)
+
+func (et EntityType) Validate() bool {
+	switch et {
+	case EntityTender, EntityBid, EntityEmployee:
+		return true
+	default:
+		return false
+	}
+}
+
+type AuditAction string
+
+const (
+	AuditTenderCreate   AuditAction = "tender.create"
+	AuditTenderEdit     AuditAction = "tender.edit"
+	AuditTenderStatus   AuditAction = "tender.status"
+	AuditTenderRollback AuditAction = "tender.rollback"
+	AuditTenderClose    AuditAction = "tender.close"
+	AuditBidCreate      AuditAction = "bid.create"
+	AuditBidEdit        AuditAction = "bid.edit"
+	AuditBidStatus      AuditAction = "bid.status"
+	AuditBidRollback    AuditAction = "bid.rollback"
+	AuditBidApprove     AuditAction = "bid.approve"
+	AuditBidReject      AuditAction = "bid.reject"
+	AuditBidFeedback    AuditAction = "bid.feedback"
+	AuditBidMessage     AuditAction = "bid.message"
+	AuditBidMessageEdit AuditAction = "bid.message.edit"
+	AuditBidMessageDrop AuditAction = "bid.message.delete"
+	AuditBidRate        AuditAction = "bid.rate"
+	AuditRoleGrant      AuditAction = "role.grant"
+	AuditRoleRevoke     AuditAction = "role.revoke"
+)
+
+var AuditActions = []AuditAction{
+	AuditTenderCreate, AuditTenderEdit, AuditTenderStatus, AuditTenderRollback, AuditTenderClose,
+	AuditBidCreate, AuditBidEdit, AuditBidStatus, AuditBidRollback, AuditBidApprove, AuditBidReject,
+	AuditBidFeedback, AuditBidMessage, AuditBidMessageEdit, AuditBidMessageDrop, AuditBidRate,
+	AuditRoleGrant, AuditRoleRevoke,
+}
+
+func (aa AuditAction) Validate() bool {
+	return slices.Contains(AuditActions, aa)
+}
+
+type AuditEvent struct {
+	Id             uuid.UUID   `db:"id"`
+	ActorId        *uuid.UUID  `db:"actor_id"`
+	OrganizationId uuid.UUID   `db:"organization_id"`
+	EntityType     EntityType  `db:"entity_type"`
+	EntityId       uuid.UUID   `db:"entity_id"`
+	Action         AuditAction `db:"action"`
+	BeforeVersion  *int        `db:"before_version"`
+	AfterVersion   *int        `db:"after_version"`
+	RequestId      *string     `db:"request_id"`
+	CreatedAt      time.Time   `db:"created_at"`
+
+	Subject any `db:"-"`
+}
+
+func (e AuditEvent) About(subject any) AuditEvent {
+	e.Subject = subject
+	return e
+}
+
+func (e AuditEvent) VisibleTo(side FeedbackSide) bool {
+	f, ok := e.Subject.(BidFeedback)
+	return !ok || f.VisibleTo(side)
+}
+
+func (e AuditEvent) Cursor(s Sort) Cursor {
+	return Cursor{Sort: s, Key: e.CreatedAt, Id: e.Id}
+}
+
+type AuditFilter struct {
+	EntityType EntityType
+	EntityId   *uuid.UUID
+	Actor      string
+}
+
+func version(v int) *int {
+	return &v
+}
+
+func TenderEvent(action AuditAction, actorId *uuid.UUID, before *Tender, after Tender) AuditEvent {
+
+	e := AuditEvent{
+		ActorId:        actorId,
+		OrganizationId: after.OrganizationId,
+		EntityType:     EntityTender,
+		EntityId:       after.Id,
+		Action:         action,
+		AfterVersion:   version(int(after.Version)),
+		Subject:        after,
+	}
+	if before != nil {
+		e.BeforeVersion = version(int(before.Version))
+	}
+
+	return e
+
+}
+
+func BidEvent(action AuditAction, actorId *uuid.UUID, orgId uuid.UUID, before *Bid, after Bid) AuditEvent {
+
+	e := AuditEvent{
+		ActorId:        actorId,
+		OrganizationId: orgId,
+		EntityType:     EntityBid,
+		EntityId:       after.Id,
+		Action:         action,
+		AfterVersion:   version(after.Version),
+		Subject:        after,
+	}
+	if before != nil {
+		e.BeforeVersion = version(before.Version)
+	}
+
+	return e
+
+}
+
+func RoleEvent(action AuditAction, actorId uuid.UUID, orgId uuid.UUID, memberId uuid.UUID) AuditEvent {
+	return AuditEvent{
+		ActorId:        &actorId,
+		OrganizationId: orgId,
+		EntityType:     EntityEmployee,
+		EntityId:       memberId,
+		Action:         action,
+	}
+}
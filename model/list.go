@@ -0,0 +1,200 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type Sort string
+
+const (
+	SortName      Sort = "name"
+	SortCreatedAt Sort = "created_at"
+	SortRank      Sort = "-rank"
+)
+
+var TenderSorts = []string{"name", "created_at", "updated_at"}
+var SearchSorts = []string{"rank", "name", "created_at"}
+var BidSorts = []string{"name", "created_at", "updated_at", "price"}
+var FeedbackSorts = []string{"created_at"}
+var AuditSorts = []string{"created_at"}
+var RatingSorts = []string{"created_at"}
+var NotificationSorts = []string{"created_at"}
+var DeliverySorts = []string{"created_at"}
+
+func (s Sort) Field() string {
+	return strings.TrimPrefix(string(s), "-")
+}
+
+func (s Sort) Desc() bool {
+	return strings.HasPrefix(string(s), "-")
+}
+
+func (s Sort) Validate(fields []string) bool {
+	return slices.Contains(fields, s.Field())
+}
+
+type Cursor struct {
+	Sort Sort      `json:"s"`
+	Key  any       `json:"k"`
+	Id   uuid.UUID `json:"i"`
+}
+
+func (c Cursor) Encode() string {
+
+	bytes, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(bytes)
+
+}
+
+func DecodeCursor(token string) (Cursor, bool) {
+
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, false
+	}
+
+	c := Cursor{}
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return Cursor{}, false
+	}
+
+	switch key := c.Key.(type) {
+	case string:
+		switch c.Sort.Field() {
+		case "name":
+		case "created_at", "updated_at":
+			t, err := time.Parse(time.RFC3339Nano, key)
+			if err != nil {
+				return Cursor{}, false
+			}
+			c.Key = t
+		default:
+			return Cursor{}, false
+		}
+	case json.Number:
+		switch c.Sort.Field() {
+		case "price":
+			price, err := ParseMoney(key.String())
+			if err != nil {
+				return Cursor{}, false
+			}
+			c.Key = price
+		case "rank":
+			rank, err := strconv.ParseFloat(key.String(), 32)
+			if err != nil {
+				return Cursor{}, false
+			}
+			c.Key = float32(rank)
+		default:
+			return Cursor{}, false
+		}
+	case nil:
+		if c.Sort.Field() != "price" {
+			return Cursor{}, false
+		}
+	default:
+		return Cursor{}, false
+	}
+
+	return c, true
+
+}
+
+type Listable interface {
+	Cursor(s Sort) Cursor
+}
+
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Sort   Sort
+	After  *Cursor
+	Total  bool
+}
+
+type Page[T Listable] struct {
+	Items []T
+	Next  *Cursor
+	Total *int
+}
+
+func NewPage[T Listable](items []T, opts ListOptions) Page[T] {
+
+	if len(items) <= opts.Limit {
+		return Page[T]{Items: items}
+	}
+
+	items = items[:opts.Limit]
+	if len(items) == 0 {
+		return Page[T]{Items: items}
+	}
+
+	next := items[len(items)-1].Cursor(opts.Sort)
+
+	return Page[T]{Items: items, Next: &next}
+
+}
+
+func (t Tender) Cursor(s Sort) Cursor {
+
+	c := Cursor{Sort: s, Id: t.Id}
+	switch s.Field() {
+	case "created_at":
+		c.Key = t.CreatedAt
+	case "updated_at":
+		c.Key = t.UpdatedAt
+	default:
+		c.Key = t.Name
+	}
+
+	return c
+
+}
+
+func (b Bid) Cursor(s Sort) Cursor {
+
+	c := Cursor{Sort: s, Id: b.Id}
+	switch s.Field() {
+	case "created_at":
+		c.Key = b.CreatedAt
+	case "updated_at":
+		c.Key = b.UpdatedAt
+	case "price":
+		if b.Price != nil {
+			c.Key = *b.Price
+		}
+	default:
+		c.Key = b.Name
+	}
+
+	return c
+
+}
+
+func (m TenderMatch) Cursor(s Sort) Cursor {
+
+	if s.Field() == "rank" {
+		return Cursor{Sort: s, Key: m.Rank, Id: m.Id}
+	}
+
+	return m.Tender.Cursor(s)
+
+}
+
+func (f BidFeedback) Cursor(s Sort) Cursor {
+	return Cursor{Sort: s, Key: f.CreatedAt, Id: f.Id}
+}
+
+func (r BidRating) Cursor(s Sort) Cursor {
+	return Cursor{Sort: s, Key: r.CreatedAt, Id: r.Id}
+}
@@ -1,12 +1,18 @@
 package model
 
 import (
-	"fmt"
+	"regexp"
+	"slices"
 	"time"
 
 	"github.com/gofrs/uuid"
 )
 
+type Employee struct {
+	Id       uuid.UUID `json:"id" db:"id"`
+	Username string    `json:"username" db:"username"`
+}
+
 type Tender struct {
 	Id              uuid.UUID         `json:"id" db:"id"`
 	Name            string            `json:"name" db:"name"`
@@ -18,13 +24,159 @@ type Tender struct {
 	CreatedAt       time.Time         `json:"createdAt" db:"created_at"`
 	UpdatedAt       time.Time         `json:"updatedAt" db:"updated_at"`
 	CreatorUsername string            `json:"creatorUsername" db:"-"`
+
+	SubmissionDeadline *time.Time `json:"submissionDeadline" db:"submission_deadline"`
+	DecisionDeadline   *time.Time `json:"decisionDeadline" db:"decision_deadline"`
+	CloseReason        *string    `json:"-" db:"close_reason"`
+
+	BudgetMin    *Money       `json:"budgetMin" db:"budget_min"`
+	BudgetMax    *Money       `json:"budgetMax" db:"budget_max"`
+	Currency     *string      `json:"currency" db:"currency"`
+	BudgetPolicy BudgetPolicy `json:"budgetPolicy" db:"budget_policy"`
+
+	ApprovalRule      ApprovalRule  `json:"approvalRule" db:"approval_rule"`
+	ApprovalThreshold *int          `json:"approvalThreshold" db:"approval_threshold"`
+	RejectionRule     RejectionRule `json:"rejectionRule" db:"rejection_rule"`
+	MaxAwards         *int          `json:"maxAwards" db:"max_awards"`
+
+	ModifiedBy *uuid.UUID `json:"-" db:"modified_by"`
+	Operation  Operation  `json:"-" db:"operation"`
+}
+
+type TenderMatch struct {
+	Tender
+	Rank    float32 `db:"rank"`
+	Snippet string  `db:"snippet"`
 }
 
-func (t *Tender) MarshalJSON() ([]byte, error) {
-	str := fmt.Sprintf(`{"id":"%s","name":"%s","description":"%s","status":"%s","serviceType":"%s","version":%d,"createdAt":"%s"}`,
-		t.Id.String(), t.Name, t.Description, t.Status, t.ServiceType, t.Version, t.CreatedAt.Format(time.RFC3339))
+func (t Tender) ValidateBudget() bool {
+
+	if t.BudgetMin == nil && t.BudgetMax == nil {
+		return t.Currency == nil || ValidateCurrency(*t.Currency)
+	}
+
+	if t.Currency == nil || !ValidateCurrency(*t.Currency) {
+		return false
+	}
+
+	if (t.BudgetMin != nil && *t.BudgetMin < 0) || (t.BudgetMax != nil && *t.BudgetMax < 0) {
+		return false
+	}
+
+	return t.BudgetMin == nil || t.BudgetMax == nil || *t.BudgetMin <= *t.BudgetMax
+
+}
+
+func (t Tender) FitsBudget(b Bid) bool {
+
+	if b.Price == nil || (t.BudgetMin == nil && t.BudgetMax == nil) {
+		return true
+	}
+
+	if t.Currency == nil || b.Currency == nil || *t.Currency != *b.Currency {
+		return false
+	}
+
+	return (t.BudgetMin == nil || *b.Price >= *t.BudgetMin) && (t.BudgetMax == nil || *b.Price <= *t.BudgetMax)
+
+}
+
+func (t Tender) ValidatePolicy() bool {
+
+	if len(t.RejectionRule) != 0 && !t.RejectionRule.Validate() {
+		return false
+	}
+
+	switch t.ApprovalRule {
+	case ApprovalRuleFixed:
+		return t.ApprovalThreshold != nil && *t.ApprovalThreshold > 0
+	case ApprovalRulePercent:
+		return t.ApprovalThreshold != nil && *t.ApprovalThreshold > 0 && *t.ApprovalThreshold <= 100
+	case "", ApprovalRuleQuorum, ApprovalRuleUnanimous, ApprovalRuleFirstApprover:
+		return t.ApprovalThreshold == nil
+	default:
+		return false
+	}
+
+}
+
+func (t Tender) Awards() int {
+
+	if t.MaxAwards == nil {
+		return 1
+	}
+
+	return *t.MaxAwards
+
+}
+
+func (t Tender) RequiredApprovals(voters int) int {
+
+	rule := t.ApprovalRule
+	if t.ApprovalThreshold == nil && (rule == ApprovalRuleFixed || rule == ApprovalRulePercent) {
+		rule = ApprovalRuleQuorum
+	}
+
+	required := 1
+	switch rule {
+	case ApprovalRuleQuorum:
+		required = min(3, voters)
+	case ApprovalRuleFixed:
+		required = min(*t.ApprovalThreshold, voters)
+	case ApprovalRulePercent:
+		required = (*t.ApprovalThreshold*voters + 99) / 100
+	case ApprovalRuleUnanimous:
+		required = voters
+	}
+
+	return max(1, required)
+
+}
+
+func (t Tender) RequiredRejections(voters int) int {
+
+	if t.RejectionRule == RejectionRuleMajority {
+		return voters/2 + 1
+	}
+
+	return 1
+
+}
+
+func ValidateDeadlines(merged, changed Tender, now time.Time) bool {
+
+	for _, d := range []*time.Time{changed.SubmissionDeadline, changed.DecisionDeadline} {
+		if d != nil && !d.After(now) {
+			return false
+		}
+	}
+
+	return merged.SubmissionDeadline == nil || merged.DecisionDeadline == nil || !merged.DecisionDeadline.Before(*merged.SubmissionDeadline)
+
+}
+
+func (t Tender) RejectionFeedback() string {
+
+	if t.CloseReason == nil {
+		return "tender has been closed"
+	}
+
+	return "tender has been closed: " + *t.CloseReason
+
+}
+
+func (t Tender) AcceptsBids(now time.Time) bool {
+	return t.SubmissionDeadline == nil || now.Before(*t.SubmissionDeadline)
+}
+
+func (t Tender) Expired(now time.Time) (string, bool) {
+
+	if t.Status != TenderStatusPublished || t.DecisionDeadline == nil {
+		return "", false
+	}
+
+	return TenderCloseReasonDecisionDeadline, !now.Before(*t.DecisionDeadline)
 
-	return []byte(str), nil
 }
 
 type Bid struct {
@@ -38,29 +190,220 @@ type Bid struct {
 	Version     int           `json:"version" db:"version"`
 	CreatedAt   time.Time     `json:"createdAt" db:"created_at"`
 	UpdatedAt   time.Time     `json:"updatedAt" db:"updated_at"`
+
+	Price        *Money     `json:"price" db:"price"`
+	Currency     *string    `json:"currency" db:"currency"`
+	DeliveryDays *int       `json:"deliveryDays" db:"delivery_days"`
+	ValidUntil   *time.Time `json:"validUntil" db:"valid_until"`
+	OutOfBudget  bool       `json:"-" db:"out_of_budget"`
+
+	ModifiedBy *uuid.UUID `json:"-" db:"modified_by"`
+	Operation  Operation  `json:"-" db:"operation"`
 }
 
-func (b *Bid) MarshalJSON() ([]byte, error) {
-	str := fmt.Sprintf(`{"id":"%s","name":"%s","status":"%s","authorType":"%s","authorId":"%s","version":%d,"createdAt":"%s"}`,
-		b.Id.String(), b.Name, b.Status, b.AuthorType, b.AuthorId.String(), b.Version, b.CreatedAt.Format(time.RFC3339))
+func (b Bid) ValidateTerms(t Tender) bool {
+
+	if b.Price != nil && (*b.Price < 0 || b.Currency == nil) {
+		return false
+	}
+
+	if b.Currency != nil && !ValidateCurrency(*b.Currency) {
+		return false
+	}
+
+	if b.ValidUntil != nil && t.DecisionDeadline != nil && b.ValidUntil.After(*t.DecisionDeadline) {
+		return false
+	}
+
+	return b.DeliveryDays == nil || *b.DeliveryDays >= 0
 
-	return []byte(str), nil
 }
 
 type BidFeedback struct {
-	Id          uuid.UUID `json:"id" db:"id"`
-	TenderId    uuid.UUID `json:"tenderId" db:"tender_id"`
-	BidId       uuid.UUID `json:"bidId" db:"bid_id"`
-	UserId      uuid.UUID `json:"userId" db:"user_id"`
-	Description string    `json:"description" db:"description"`
-	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	Id          uuid.UUID  `json:"id" db:"id"`
+	TenderId    uuid.UUID  `json:"tenderId" db:"tender_id"`
+	BidId       uuid.UUID  `json:"bidId" db:"bid_id"`
+	UserId      *uuid.UUID `json:"userId" db:"user_id"`
+	Description string     `json:"description" db:"description"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+
+	ParentId   *uuid.UUID         `json:"parentId" db:"parent_id"`
+	Side       FeedbackSide       `json:"side" db:"side"`
+	Visibility FeedbackVisibility `json:"visibility" db:"visibility"`
+	UpdatedAt  *time.Time         `json:"updatedAt" db:"updated_at"`
+	DeletedAt  *time.Time         `json:"deletedAt" db:"deleted_at"`
+}
+
+const FeedbackEditWindow = 15 * time.Minute
+
+func (f BidFeedback) VisibleTo(side FeedbackSide) bool {
+	return f.Visibility != FeedbackVisibilityInternal || f.Side == side
+}
+
+func (f BidFeedback) Editable(now time.Time) bool {
+	return f.DeletedAt == nil && now.Sub(f.CreatedAt) < FeedbackEditWindow
+}
+
+type FeedbackSide string
+
+const (
+	FeedbackSideReviewer FeedbackSide = "Reviewer"
+	FeedbackSideAuthor   FeedbackSide = "Author"
+	FeedbackSideSystem   FeedbackSide = "System"
+)
+
+type FeedbackVisibility string
+
+const (
+	FeedbackVisibilityPublic   FeedbackVisibility = "Public"
+	FeedbackVisibilityInternal FeedbackVisibility = "Internal"
+)
+
+func (fv FeedbackVisibility) Validate() bool {
+	switch fv {
+	case FeedbackVisibilityPublic, FeedbackVisibilityInternal:
+		return true
+	default:
+		return false
+	}
 }
 
-func (b *BidFeedback) MarshalJSON() ([]byte, error) {
-	str := fmt.Sprintf(`{"id":"%s","description":"%s","createdAt":"%s"}`,
-		b.Id.String(), b.Description, b.CreatedAt.Format(time.RFC3339))
+type BidDecision struct {
+	BidId     uuid.UUID `json:"bidId" db:"bid_id"`
+	UserId    uuid.UUID `json:"userId" db:"user_id"`
+	Username  string    `json:"username" db:"username"`
+	Decision  BidStatus `json:"decision" db:"decision"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
 
-	return []byte(str), nil
+type DecisionTally struct {
+	Tender             Tender
+	Decisions          []BidDecision
+	Pending            []Employee
+	Approvals          int
+	Rejections         int
+	RequiredApprovals  int
+	RequiredRejections int
+}
+
+func NewDecisionTally(tender Tender, decisions []BidDecision, voters []Employee) DecisionTally {
+
+	tally := DecisionTally{
+		Tender:             tender,
+		Decisions:          decisions,
+		Pending:            []Employee{},
+		RequiredApprovals:  tender.RequiredApprovals(len(voters)),
+		RequiredRejections: tender.RequiredRejections(len(voters)),
+	}
+
+	voted := map[uuid.UUID]bool{}
+	for _, d := range decisions {
+		voted[d.UserId] = true
+		if d.Decision == BidStatusApproved {
+			tally.Approvals++
+		} else {
+			tally.Rejections++
+		}
+	}
+
+	for _, v := range voters {
+		if !voted[v.Id] {
+			tally.Pending = append(tally.Pending, v)
+		}
+	}
+
+	return tally
+
+}
+
+type BidRating struct {
+	Id                   uuid.UUID  `json:"id" db:"id"`
+	BidId                uuid.UUID  `json:"bidId" db:"bid_id"`
+	TenderId             uuid.UUID  `json:"tenderId" db:"tender_id"`
+	AuthorId             uuid.UUID  `json:"authorId" db:"author_id"`
+	AuthorOrganizationId *uuid.UUID `json:"authorOrganizationId" db:"author_organization_id"`
+	UserId               *uuid.UUID `json:"userId" db:"user_id"`
+	OrganizationId       uuid.UUID  `json:"organizationId" db:"organization_id"`
+	Quality              int        `json:"quality" db:"quality"`
+	Timeliness           int        `json:"timeliness" db:"timeliness"`
+	Price                int        `json:"price" db:"price"`
+	Comment              string     `json:"comment" db:"comment"`
+	CreatedAt            time.Time  `json:"createdAt" db:"created_at"`
+}
+
+const (
+	MinRatingScore = 1
+	MaxRatingScore = 5
+)
+
+func (r BidRating) ValidateScores() bool {
+
+	for _, score := range []int{r.Quality, r.Timeliness, r.Price} {
+		if score < MinRatingScore || score > MaxRatingScore {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+type ReputationFilter struct {
+	Author         string
+	OrganizationId *uuid.UUID
+}
+
+type Reputation struct {
+	Ratings    int      `db:"ratings"`
+	Quality    *float64 `db:"quality"`
+	Timeliness *float64 `db:"timeliness"`
+	Price      *float64 `db:"price"`
+	Overall    *float64 `db:"overall"`
+}
+
+func NewReputation(ratings []BidRating) Reputation {
+
+	rep := Reputation{Ratings: len(ratings)}
+	if len(ratings) == 0 {
+		return rep
+	}
+
+	quality, timeliness, price := 0, 0, 0
+	for _, r := range ratings {
+		quality += r.Quality
+		timeliness += r.Timeliness
+		price += r.Price
+	}
+
+	average := func(sum int) *float64 {
+		avg := float64(sum) / float64(len(ratings))
+		return &avg
+	}
+
+	rep.Quality, rep.Timeliness, rep.Price = average(quality), average(timeliness), average(price)
+	rep.Overall = average(quality + timeliness + price)
+	*rep.Overall /= 3
+
+	return rep
+
+}
+
+type Operation string
+
+const (
+	OperationUnknown      Operation = "Unknown"
+	OperationCreate       Operation = "Create"
+	OperationEdit         Operation = "Edit"
+	OperationStatusChange Operation = "StatusChange"
+	OperationRollback     Operation = "Rollback"
+	OperationDecision     Operation = "Decision"
+	OperationClose        Operation = "Close"
+)
+
+type Versions []int
+
+func (v Versions) Match(version int) bool {
+	return v == nil || slices.Contains(v, version)
 }
 
 type TenderStatus string
@@ -80,6 +423,59 @@ func (ts TenderStatus) Validate() bool {
 	}
 }
 
+const (
+	TenderCloseReasonDecisionDeadline = "decision deadline passed"
+	TenderCloseReasonAwarded          = "all awards have been made"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func ValidateCurrency(c string) bool {
+	return currencyCode.MatchString(c)
+}
+
+type BudgetPolicy string
+
+const (
+	BudgetPolicyWarn   BudgetPolicy = "Warn"
+	BudgetPolicyReject BudgetPolicy = "Reject"
+)
+
+func (bp BudgetPolicy) Validate() bool {
+	switch bp {
+	case BudgetPolicyWarn, BudgetPolicyReject:
+		return true
+	default:
+		return false
+	}
+}
+
+type ApprovalRule string
+
+const (
+	ApprovalRuleQuorum        ApprovalRule = "Quorum"
+	ApprovalRuleFixed         ApprovalRule = "Fixed"
+	ApprovalRulePercent       ApprovalRule = "Percent"
+	ApprovalRuleUnanimous     ApprovalRule = "Unanimous"
+	ApprovalRuleFirstApprover ApprovalRule = "FirstApprover"
+)
+
+type RejectionRule string
+
+const (
+	RejectionRuleFirstVeto RejectionRule = "FirstVeto"
+	RejectionRuleMajority  RejectionRule = "Majority"
+)
+
+func (rr RejectionRule) Validate() bool {
+	switch rr {
+	case RejectionRuleFirstVeto, RejectionRuleMajority:
+		return true
+	default:
+		return false
+	}
+}
+
 type TenderServiceType string
 
 const (
@@ -140,3 +536,70 @@ func (bs BidStatus) ValidateDecision() bool {
 		return false
 	}
 }
+
+type Role string
+
+const (
+	RoleOwner    Role = "Owner"
+	RoleEditor   Role = "Editor"
+	RoleApprover Role = "Approver"
+	RoleViewer   Role = "Viewer"
+)
+
+func (r Role) Validate() bool {
+	switch r {
+	case RoleOwner, RoleEditor, RoleApprover, RoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
+type Action string
+
+const (
+	ActionView          Action = "view"
+	ActionCreateTender  Action = "create tender"
+	ActionEditTender    Action = "edit tender"
+	ActionPublishTender Action = "publish tender"
+	ActionEditBid       Action = "edit bid"
+	ActionDecideBid     Action = "decide bid"
+	ActionFeedback      Action = "feedback"
+	ActionManageRoles   Action = "manage roles"
+	ActionManageHooks   Action = "manage webhooks"
+)
+
+var rolePermissions = map[Role][]Action{
+	RoleOwner:    {ActionView, ActionCreateTender, ActionEditTender, ActionPublishTender, ActionEditBid, ActionDecideBid, ActionFeedback, ActionManageRoles, ActionManageHooks},
+	RoleEditor:   {ActionView, ActionCreateTender, ActionEditTender, ActionPublishTender, ActionEditBid},
+	RoleApprover: {ActionView, ActionDecideBid, ActionFeedback},
+	RoleViewer:   {ActionView},
+}
+
+// MemberRoles keep the baseline rights of a responsible who joins an organization that already has an Owner.
+var MemberRoles = []Role{RoleEditor, RoleApprover}
+
+func (r Role) Can(a Action) bool {
+	return slices.Contains(rolePermissions[r], a)
+}
+
+func RolesAllowedTo(a Action) []Role {
+
+	roles := []Role{}
+	for _, r := range []Role{RoleOwner, RoleEditor, RoleApprover, RoleViewer} {
+		if r.Can(a) {
+			roles = append(roles, r)
+		}
+	}
+
+	return roles
+
+}
+
+type RoleAssignment struct {
+	OrganizationId uuid.UUID `json:"organizationId" db:"organization_id"`
+	UserId         uuid.UUID `json:"userId" db:"user_id"`
+	Username       string    `json:"username" db:"username"`
+	Role           Role      `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,38 @@
+package model_test
+
+import (
+	"testing"
+	"zadanie/model"
+)
+
+func TestRequiredApprovals(t *testing.T) {
+
+	two, half := 2, 50
+	tests := []struct {
+		name      string
+		rule      model.ApprovalRule
+		threshold *int
+		voters    int
+		want      int
+	}{
+		{"quorum", model.ApprovalRuleQuorum, nil, 8, 3},
+		{"quorum of two", model.ApprovalRuleQuorum, nil, 2, 2},
+		{"fixed", model.ApprovalRuleFixed, &two, 8, 2},
+		{"percent", model.ApprovalRulePercent, &half, 5, 3},
+		{"unanimous", model.ApprovalRuleUnanimous, nil, 4, 4},
+		{"first approver", model.ApprovalRuleFirstApprover, nil, 4, 1},
+		{"fixed without threshold", model.ApprovalRuleFixed, nil, 8, 3},
+		{"percent without threshold", model.ApprovalRulePercent, nil, 8, 3},
+		{"no voters", model.ApprovalRuleUnanimous, nil, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tender := model.Tender{ApprovalRule: tt.rule, ApprovalThreshold: tt.threshold}
+			if got := tender.RequiredApprovals(tt.voters); got != tt.want {
+				t.Fatalf("%d required approvals, want %d", got, tt.want)
+			}
+		})
+	}
+
+}
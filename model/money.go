@@ -0,0 +1,107 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	moneyScale  = 2
+	moneyDigits = 16
+)
+
+var ErrIncorrectMoney = errors.New("amount must be a decimal with at most 2 fraction digits and 16 digits in total")
+
+type Money int64
+
+func ParseMoney(s string) (Money, error) {
+
+	negative := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+
+	if len(whole) == 0 || len(frac) > moneyScale || len(whole)+moneyScale > moneyDigits ||
+		strings.Trim(whole+frac, "0123456789") != "" {
+		return 0, ErrIncorrectMoney
+	}
+
+	units, err := strconv.ParseInt(whole+frac+strings.Repeat("0", moneyScale-len(frac)), 10, 64)
+	if err != nil {
+		return 0, ErrIncorrectMoney
+	}
+
+	if negative {
+		units = -units
+	}
+
+	return Money(units), nil
+
+}
+
+func (m Money) String() string {
+
+	units := int64(m)
+	sign := ""
+	if units < 0 {
+		sign, units = "-", -units
+	}
+
+	s := strconv.FormatInt(units, 10)
+	if len(s) <= moneyScale {
+		s = strings.Repeat("0", moneyScale-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-moneyScale] + "." + s[len(s)-moneyScale:]
+
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+
+	parsed, err := ParseMoney(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+
+}
+
+func (m *Money) ScanNumeric(v pgtype.Numeric) error {
+
+	if !v.Valid || v.NaN || v.InfinityModifier != pgtype.Finite {
+		return ErrIncorrectMoney
+	}
+
+	units := new(big.Int).Set(v.Int)
+	exp := int64(v.Exp) + moneyScale
+
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(max(exp, -exp)), nil)
+	if exp >= 0 {
+		units.Mul(units, pow)
+	} else if _, rem := units.QuoRem(units, pow, new(big.Int)); rem.Sign() != 0 {
+		return ErrIncorrectMoney
+	}
+
+	if !units.IsInt64() {
+		return ErrIncorrectMoney
+	}
+
+	*m = Money(units.Int64())
+
+	return nil
+
+}
+
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(m)), Exp: -moneyScale, Valid: true}, nil
+}
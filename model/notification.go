@@ -0,0 +1,172 @@
+package model
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type NotificationChannel string
+
+const (
+	NotificationChannelInbox NotificationChannel = "Inbox"
+	NotificationChannelEmail NotificationChannel = "Email"
+)
+
+var NotificationChannels = []NotificationChannel{NotificationChannelInbox, NotificationChannelEmail}
+
+func (nc NotificationChannel) Validate() bool {
+	return slices.Contains(NotificationChannels, nc)
+}
+
+type Notification struct {
+	Id             uuid.UUID   `json:"id" db:"id"`
+	UserId         uuid.UUID   `json:"userId" db:"user_id"`
+	EventId        uuid.UUID   `json:"eventId" db:"event_id"`
+	ActorId        *uuid.UUID  `json:"actorId" db:"actor_id"`
+	OrganizationId uuid.UUID   `json:"organizationId" db:"organization_id"`
+	EntityType     EntityType  `json:"entityType" db:"entity_type"`
+	EntityId       uuid.UUID   `json:"entityId" db:"entity_id"`
+	Action         AuditAction `json:"action" db:"action"`
+	Message        string      `json:"message" db:"message"`
+	CreatedAt      time.Time   `json:"createdAt" db:"created_at"`
+	ReadAt         *time.Time  `json:"readAt" db:"read_at"`
+}
+
+func NewNotification(userId uuid.UUID, e AuditEvent) Notification {
+	return Notification{
+		Id:             uuid.Must(uuid.NewV4()),
+		UserId:         userId,
+		EventId:        e.Id,
+		ActorId:        e.ActorId,
+		OrganizationId: e.OrganizationId,
+		EntityType:     e.EntityType,
+		EntityId:       e.EntityId,
+		Action:         e.Action,
+		Message:        e.Message(),
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+func (n Notification) Cursor(s Sort) Cursor {
+	return Cursor{Sort: s, Key: n.CreatedAt, Id: n.Id}
+}
+
+var notificationMessages = map[AuditAction]string{
+	AuditTenderCreate:   "tender %s was created",
+	AuditTenderEdit:     "tender %s was edited",
+	AuditTenderStatus:   "tender %s changed its status",
+	AuditTenderRollback: "tender %s was rolled back",
+	AuditTenderClose:    "tender %s was closed",
+	AuditBidCreate:      "bid %s was created",
+	AuditBidEdit:        "bid %s was edited",
+	AuditBidStatus:      "bid %s changed its status",
+	AuditBidRollback:    "bid %s was rolled back",
+	AuditBidApprove:     "bid %s received an approval",
+	AuditBidReject:      "bid %s was rejected",
+	AuditBidFeedback:    "bid %s received feedback",
+	AuditBidMessage:     "bid %s has a new message",
+	AuditBidMessageEdit: "a message on bid %s was edited",
+	AuditBidMessageDrop: "a message on bid %s was deleted",
+	AuditBidRate:        "bid %s was rated",
+	AuditRoleGrant:      "employee %s was granted a role",
+	AuditRoleRevoke:     "employee %s lost a role",
+}
+
+func (e AuditEvent) Message() string {
+
+	format, ok := notificationMessages[e.Action]
+	if !ok {
+		return string(e.Action)
+	}
+
+	return fmt.Sprintf(format, e.EntityId)
+
+}
+
+type NotificationFilter struct {
+	Unread bool
+}
+
+type Subscription struct {
+	UserId    uuid.UUID           `json:"-" db:"user_id"`
+	Channel   NotificationChannel `json:"channel" db:"channel"`
+	Enabled   bool                `json:"enabled" db:"enabled"`
+	Target    *string             `json:"target" db:"target"`
+	Actions   []AuditAction       `json:"actions" db:"actions"`
+	UpdatedAt *time.Time          `json:"-" db:"updated_at"`
+}
+
+func DefaultSubscription(userId uuid.UUID, channel NotificationChannel) Subscription {
+	return Subscription{
+		UserId:  userId,
+		Channel: channel,
+		Enabled: channel == NotificationChannelInbox,
+		Actions: []AuditAction{},
+	}
+}
+
+func EffectiveSubscriptions(userId uuid.UUID, stored []Subscription) []Subscription {
+
+	subs := make([]Subscription, 0, len(NotificationChannels))
+	for _, channel := range NotificationChannels {
+		sub := DefaultSubscription(userId, channel)
+		for _, s := range stored {
+			if s.Channel == channel {
+				sub = s
+				break
+			}
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs
+
+}
+
+func (s Subscription) Validate() bool {
+
+	if !s.Channel.Validate() {
+		return false
+	}
+
+	for _, a := range s.Actions {
+		if !a.Validate() {
+			return false
+		}
+	}
+
+	if s.Target == nil {
+		return !s.Enabled || s.Channel == NotificationChannelInbox
+	}
+
+	switch s.Channel {
+	case NotificationChannelEmail:
+		_, err := mail.ParseAddress(*s.Target)
+		return err == nil
+	default:
+		return false
+	}
+
+}
+
+func (s Subscription) Normalize() Subscription {
+
+	if s.Target == nil || s.Channel != NotificationChannelEmail {
+		return s
+	}
+
+	if addr, err := mail.ParseAddress(*s.Target); err == nil {
+		s.Target = &addr.Address
+	}
+
+	return s
+
+}
+
+func (s Subscription) Wants(action AuditAction) bool {
+	return s.Enabled && (len(s.Actions) == 0 || slices.Contains(s.Actions, action))
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type TenderChange struct {
+	Id         uuid.UUID   `json:"id"`
+	TenderId   uuid.UUID   `json:"tenderId"`
+	Action     AuditAction `json:"action"`
+	EntityType EntityType  `json:"entityType"`
+	EntityId   uuid.UUID   `json:"entityId"`
+	Status     string      `json:"status,omitempty"`
+	MessageId  *uuid.UUID  `json:"messageId,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+
+	TenderOrganizationId uuid.UUID          `json:"tenderOrganizationId"`
+	BidOrganizationId    *uuid.UUID         `json:"bidOrganizationId,omitempty"`
+	BidStatus            BidStatus          `json:"bidStatus,omitempty"`
+	Side                 FeedbackSide       `json:"side,omitempty"`
+	Visibility           FeedbackVisibility `json:"visibility,omitempty"`
+}
+
+func (e AuditEvent) TenderChange() (TenderChange, bool) {
+
+	c := TenderChange{
+		Id:         e.Id,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityId:   e.EntityId,
+		CreatedAt:  e.CreatedAt,
+	}
+
+	switch subject := e.Subject.(type) {
+	case Tender:
+		if e.Action == AuditTenderCreate {
+			return TenderChange{}, false
+		}
+		c.TenderId = subject.Id
+		c.TenderOrganizationId = subject.OrganizationId
+		c.Status = string(subject.Status)
+	case Bid:
+		if e.Action == AuditBidRate {
+			return TenderChange{}, false
+		}
+		c.TenderId = subject.TenderId
+		c.Status = string(subject.Status)
+		c.BidStatus = subject.Status
+	case BidFeedback:
+		c.TenderId = subject.TenderId
+		c.MessageId = &subject.Id
+		c.Side = subject.Side
+		c.Visibility = subject.Visibility
+	default:
+		return TenderChange{}, false
+	}
+
+	return c, true
+
+}
+
+func (c TenderChange) VisibleTo(orgId uuid.UUID) bool {
+
+	if c.EntityType == EntityTender {
+		return (orgId != uuid.Nil && c.TenderOrganizationId == orgId) ||
+			c.Status == string(TenderStatusPublished) || c.Status == string(TenderStatusClosed)
+	}
+
+	if orgId == uuid.Nil {
+		return false
+	}
+
+	bidSide := c.BidOrganizationId != nil && *c.BidOrganizationId == orgId
+	tenderSide := c.TenderOrganizationId == orgId &&
+		(c.BidStatus == BidStatusPublished || c.Action == AuditBidApprove || c.Action == AuditBidReject)
+
+	if c.MessageId != nil && c.Visibility == FeedbackVisibilityInternal {
+		bidSide = bidSide && c.Side == FeedbackSideAuthor
+		tenderSide = tenderSide && c.Side == FeedbackSideReviewer
+	}
+
+	return bidSide || tenderSide
+
+}
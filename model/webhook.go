@@ -0,0 +1,201 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookTenderPublished WebhookEventType = "tender.published"
+	WebhookTenderClosed    WebhookEventType = "tender.closed"
+	WebhookBidCreated      WebhookEventType = "bid.created"
+	WebhookBidApproved     WebhookEventType = "bid.approved"
+	WebhookBidRejected     WebhookEventType = "bid.rejected"
+	WebhookFeedbackCreated WebhookEventType = "feedback.created"
+)
+
+var WebhookEventTypes = []WebhookEventType{
+	WebhookTenderPublished, WebhookTenderClosed, WebhookBidCreated, WebhookBidApproved, WebhookBidRejected, WebhookFeedbackCreated,
+}
+
+func (wet WebhookEventType) Validate() bool {
+	return slices.Contains(WebhookEventTypes, wet)
+}
+
+func (e AuditEvent) WebhookEvent() (WebhookEventType, bool) {
+
+	switch subject := e.Subject.(type) {
+	case Tender:
+		switch {
+		case e.Action == AuditTenderClose, e.Action == AuditTenderStatus && subject.Status == TenderStatusClosed:
+			return WebhookTenderClosed, true
+		case e.Action == AuditTenderStatus && subject.Status == TenderStatusPublished:
+			return WebhookTenderPublished, true
+		}
+	case Bid:
+		switch {
+		case e.Action == AuditBidCreate:
+			return WebhookBidCreated, true
+		case e.Action == AuditBidApprove && subject.Status == BidStatusApproved:
+			return WebhookBidApproved, true
+		case e.Action == AuditBidReject && subject.Status == BidStatusRejected:
+			return WebhookBidRejected, true
+		}
+	case BidFeedback:
+		if (e.Action == AuditBidFeedback || e.Action == AuditBidMessage) && subject.Visibility != FeedbackVisibilityInternal {
+			return WebhookFeedbackCreated, true
+		}
+	}
+
+	return "", false
+
+}
+
+type WebhookEndpoint struct {
+	Id             uuid.UUID          `json:"id" db:"id"`
+	OrganizationId uuid.UUID          `json:"organizationId" db:"organization_id"`
+	URL            string             `json:"url" db:"url"`
+	Secret         string             `json:"-" db:"secret"`
+	EventTypes     []WebhookEventType `json:"eventTypes" db:"event_types"`
+	Enabled        *bool              `json:"enabled" db:"enabled"`
+	CreatedBy      *uuid.UUID         `json:"-" db:"created_by"`
+	CreatedAt      time.Time          `json:"-" db:"created_at"`
+}
+
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+var internalSuffixes = []string{".localhost", ".local", ".localdomain", ".internal", ".lan", ".home.arpa"}
+
+func PublicAddr(addr netip.Addr) bool {
+
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, p := range reservedPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+func PublicHost(host string) bool {
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return PublicAddr(addr)
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return false
+	}
+
+	for _, suffix := range internalSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+func (we WebhookEndpoint) Validate() bool {
+
+	u, err := url.Parse(we.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 || !PublicHost(u.Hostname()) {
+		return false
+	}
+
+	if len(we.EventTypes) == 0 {
+		return false
+	}
+
+	for _, t := range we.EventTypes {
+		if !t.Validate() {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+func (we WebhookEndpoint) Wants(t WebhookEventType) bool {
+	return (we.Enabled == nil || *we.Enabled) && slices.Contains(we.EventTypes, t)
+}
+
+func NewWebhookSecret() string {
+
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+
+	return hex.EncodeToString(secret)
+
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "Pending"
+	DeliveryStatusDelivered DeliveryStatus = "Delivered"
+	DeliveryStatusDead      DeliveryStatus = "Dead"
+)
+
+func (ds DeliveryStatus) Validate() bool {
+	switch ds {
+	case DeliveryStatusPending, DeliveryStatusDelivered, DeliveryStatusDead:
+		return true
+	default:
+		return false
+	}
+}
+
+type WebhookDelivery struct {
+	Id             uuid.UUID        `db:"id"`
+	EndpointId     uuid.UUID        `db:"endpoint_id"`
+	OrganizationId uuid.UUID        `db:"organization_id"`
+	EventId        uuid.UUID        `db:"event_id"`
+	EventType      WebhookEventType `db:"event_type"`
+	Payload        []byte           `db:"payload"`
+	Status         DeliveryStatus   `db:"status"`
+	Attempts       int              `db:"attempts"`
+	NextAttemptAt  time.Time        `db:"next_attempt_at"`
+	LastError      *string          `db:"last_error"`
+	ResponseCode   *int             `db:"response_code"`
+	CreatedAt      time.Time        `db:"created_at"`
+	DeliveredAt    *time.Time       `db:"delivered_at"`
+
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+func (d WebhookDelivery) Cursor(s Sort) Cursor {
+	return Cursor{Sort: s, Key: d.CreatedAt, Id: d.Id}
+}
+
+type DeliveryFilter struct {
+	EndpointId uuid.UUID
+	Status     DeliveryStatus
+}
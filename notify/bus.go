@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type Directory interface {
+	NotificationRecipients(ctx context.Context, e model.AuditEvent) ([]uuid.UUID, error)
+	UserSubscriptions(ctx context.Context, userId uuid.UUID) ([]model.Subscription, error)
+}
+
+type Notifier interface {
+	Channel() model.NotificationChannel
+	Notify(ctx context.Context, sub model.Subscription, n model.Notification) error
+}
+
+type Bus struct {
+	events    chan model.AuditEvent
+	dir       Directory
+	notifiers map[model.NotificationChannel]Notifier
+	dropped   atomic.Int64
+}
+
+func NewBus(dir Directory, size int, notifiers ...Notifier) *Bus {
+
+	b := &Bus{
+		events:    make(chan model.AuditEvent, size),
+		dir:       dir,
+		notifiers: map[model.NotificationChannel]Notifier{},
+	}
+	for _, n := range notifiers {
+		b.notifiers[n.Channel()] = n
+	}
+
+	return b
+
+}
+
+func (b *Bus) Publish(events ...model.AuditEvent) {
+
+	if b == nil {
+		return
+	}
+
+	for _, e := range events {
+		select {
+		case b.events <- e:
+		default:
+			log.Printf("notify: queue is full, event %s (%s) dropped, %d dropped in total", e.Id, e.Action, b.dropped.Add(1))
+		}
+	}
+
+}
+
+func (b *Bus) Dropped() int64 {
+	return b.dropped.Load()
+}
+
+func (b *Bus) Run(ctx context.Context) {
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-b.events:
+			b.dispatch(ctx, e)
+		}
+	}
+
+}
+
+func (b *Bus) dispatch(ctx context.Context, e model.AuditEvent) {
+
+	recipients, err := b.dir.NotificationRecipients(ctx, e)
+	if err != nil {
+		log.Printf("notify: recipients of event %s: %s", e.Id, err.Error())
+		return
+	}
+
+	for _, userId := range recipients {
+
+		stored, err := b.dir.UserSubscriptions(ctx, userId)
+		if err != nil {
+			log.Printf("notify: subscriptions of user %s: %s", userId, err.Error())
+			continue
+		}
+
+		n := model.NewNotification(userId, e)
+		for _, sub := range model.EffectiveSubscriptions(userId, stored) {
+
+			notifier, ok := b.notifiers[sub.Channel]
+			if !ok || !sub.Wants(e.Action) {
+				continue
+			}
+
+			if err := notifier.Notify(ctx, sub, n); err != nil {
+				log.Printf("notify: %s to user %s: %s", sub.Channel, userId, err.Error())
+			}
+
+		}
+
+	}
+
+}
@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+	"zadanie/config"
+	"zadanie/model"
+)
+
+var ErrNoTarget = errors.New("subscription has no target")
+var ErrMailQueueFull = errors.New("mail queue is full")
+
+type letter struct {
+	to *mail.Address
+	n  model.Notification
+}
+
+type Email struct {
+	cfg     config.SMTP
+	letters chan letter
+}
+
+func NewEmail(cfg config.SMTP) *Email {
+	return &Email{cfg: cfg, letters: make(chan letter, cfg.QueueSize)}
+}
+
+func (e *Email) Channel() model.NotificationChannel {
+	return model.NotificationChannelEmail
+}
+
+func (e *Email) Notify(ctx context.Context, sub model.Subscription, n model.Notification) error {
+
+	if sub.Target == nil {
+		return ErrNoTarget
+	}
+
+	to, err := mail.ParseAddress(*sub.Target)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case e.letters <- letter{to: to, n: n}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return ErrMailQueueFull
+	}
+
+}
+
+func (e *Email) Run(ctx context.Context) {
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case l := <-e.letters:
+			if err := e.send(ctx, l); err != nil {
+				log.Printf("notify: email to %s: %s", l.to.Address, err.Error())
+			}
+		}
+	}
+
+}
+
+func (e *Email) send(ctx context.Context, l letter) error {
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.cfg.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(e.cfg.Address)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if len(e.cfg.Username) != 0 {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(l.to.Address); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(e.message(l)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+
+}
+
+func (e *Email) message(l letter) []byte {
+
+	msg := strings.Join([]string{
+		"From: " + e.cfg.From,
+		"To: " + l.to.String(),
+		"Subject: " + l.n.Message,
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		l.n.Message,
+		"",
+		fmt.Sprintf("%s %s, %s", l.n.EntityType, l.n.EntityId, l.n.CreatedAt.Format("2006-01-02 15:04:05 MST")),
+		"",
+	}, "\r\n")
+
+	return []byte(msg)
+
+}
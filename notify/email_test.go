@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"testing"
+	"time"
+	"zadanie/config"
+	"zadanie/model"
+)
+
+func TestEmailQueue(t *testing.T) {
+
+	ctx := context.Background()
+	e := NewEmail(config.SMTP{Address: "127.0.0.1:25", Timeout: time.Second, QueueSize: 1})
+
+	target := "User <user@example.com>"
+	sub := model.Subscription{Channel: model.NotificationChannelEmail, Enabled: true, Target: &target}
+
+	if err := e.Notify(ctx, sub, model.Notification{}); err != nil {
+		t.Fatal(err)
+	}
+	if l := <-e.letters; l.to.Address != "user@example.com" {
+		t.Fatalf("recipient %q", l.to.Address)
+	}
+
+	if err := e.Notify(ctx, sub, model.Notification{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Notify(ctx, sub, model.Notification{}); !errors.Is(err, ErrMailQueueFull) {
+		t.Fatalf("error %v, want %v", err, ErrMailQueueFull)
+	}
+
+	hostile := "a@b.c\r\nBcc: victim@example.com"
+	sub.Target = &hostile
+	if err := e.Notify(ctx, sub, model.Notification{}); err == nil {
+		t.Fatal("target with a header injection was accepted")
+	}
+
+}
+
+func TestEmailTimeout(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	e := NewEmail(config.SMTP{Address: ln.Addr().String(), Timeout: 100 * time.Millisecond, QueueSize: 1})
+
+	start := time.Now()
+	err = e.send(context.Background(), letter{to: &mail.Address{Address: "user@example.com"}})
+	if err == nil {
+		t.Fatal("silent server did not fail the delivery")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("delivery took %s", elapsed)
+	}
+
+}
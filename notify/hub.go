@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+const hubBuffer = 64
+
+type Hub struct {
+	mu   sync.Mutex
+	subs map[uuid.UUID]map[chan model.TenderChange]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: map[uuid.UUID]map[chan model.TenderChange]struct{}{}}
+}
+
+func (h *Hub) Watch(ctx context.Context, tenderId, orgId uuid.UUID) <-chan model.TenderChange {
+
+	src := make(chan model.TenderChange, hubBuffer)
+	out := make(chan model.TenderChange)
+
+	h.mu.Lock()
+	if h.subs[tenderId] == nil {
+		h.subs[tenderId] = map[chan model.TenderChange]struct{}{}
+	}
+	h.subs[tenderId][src] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+
+		defer close(out)
+		defer func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.drop(tenderId, src)
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case c, ok := <-src:
+				if !ok {
+					return
+				}
+				if !c.VisibleTo(orgId) {
+					continue
+				}
+				select {
+				case out <- c:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+	}()
+
+	return out
+
+}
+
+func (h *Hub) Publish(c model.TenderChange) {
+
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[c.TenderId] {
+		select {
+		case ch <- c:
+		default:
+			h.drop(c.TenderId, ch)
+		}
+	}
+
+}
+
+func (h *Hub) drop(tenderId uuid.UUID, ch chan model.TenderChange) {
+
+	if _, ok := h.subs[tenderId][ch]; !ok {
+		return
+	}
+
+	delete(h.subs[tenderId], ch)
+	if len(h.subs[tenderId]) == 0 {
+		delete(h.subs, tenderId)
+	}
+	close(ch)
+
+}
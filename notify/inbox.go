@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"context"
+	"zadanie/model"
+)
+
+type InboxStorage interface {
+	AddNotification(ctx context.Context, n model.Notification) error
+}
+
+type Inbox struct {
+	s InboxStorage
+}
+
+func NewInbox(s InboxStorage) *Inbox {
+	return &Inbox{s: s}
+}
+
+func (i *Inbox) Channel() model.NotificationChannel {
+	return model.NotificationChannelInbox
+}
+
+func (i *Inbox) Notify(ctx context.Context, sub model.Subscription, n model.Notification) error {
+	return i.s.AddNotification(ctx, n)
+}
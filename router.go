@@ -0,0 +1,110 @@
+package main
+
+import (
+	_ "embed"
+	"net/http"
+	"zadanie/auth"
+	"zadanie/config"
+	"zadanie/handlers"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:embed api/openapi.yml
+var openapiSpec []byte
+
+func newRouter(storage handlers.Storage, cfg config.Config) (http.Handler, error) {
+
+	validate, err := handlers.ValidateRequests(openapiSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens handlers.TokenParser
+	if len(cfg.Auth.Secret) != 0 {
+		tokens = auth.NewIssuer(cfg.Auth.Secret, cfg.Auth.TokenTTL)
+	}
+
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Use(validate)
+	router.Use(handlers.Authenticate(tokens, cfg.Auth.UsernameCompat))
+
+	router.Route("/api", func(r chi.Router) {
+		r.Get("/tenders/{tenderId}/events", handlers.TenderEvents(storage))
+
+		r.Group(func(r chi.Router) {
+			r.Use(handlers.Timeout(cfg.RequestTimeout))
+
+			r.Get("/ping", handlers.Ping(storage))
+
+			r.Route("/tenders", func(r chi.Router) {
+				r.Get("/", handlers.Tenders(storage))
+				r.Post("/new", handlers.NewTender(storage))
+				r.Get("/search", handlers.SearchTenders(storage))
+				r.Get("/my", handlers.MyTenders(storage))
+				r.Get("/{tenderId}/status", handlers.TenderStatus(storage))
+				r.Put("/{tenderId}/status", handlers.UpdateTenderStatus(storage))
+				r.Patch("/{tenderId}/edit", handlers.EditTender(storage))
+				r.Put("/{tenderId}/rollback/{version}", handlers.RollbackTender(storage))
+				r.Get("/{tenderId}/versions", handlers.TenderVersions(storage))
+				r.Get("/{tenderId}/versions/{version}", handlers.TenderVersion(storage))
+				r.Get("/{tenderId}/diff", handlers.TenderDiff(storage))
+			})
+
+			r.Route("/bids", func(r chi.Router) {
+				r.Post("/new", handlers.NewBid(storage))
+				r.Get("/my", handlers.MyBids(storage))
+				r.Get("/{tenderId}/list", handlers.BidsList(storage))
+				r.Get("/{tenderId}/reviews", handlers.ReviewsBids(storage))
+				r.Get("/{bidId}/status", handlers.BidStatus(storage))
+				r.Put("/{bidId}/status", handlers.UpdateBidStatus(storage))
+				r.Patch("/{bidId}/edit", handlers.EditBid(storage))
+				r.Put("/{bidId}/submit_decision", handlers.SubmitDecision(storage))
+				r.Get("/{bidId}/decisions", handlers.BidDecisions(storage))
+				r.Put("/{bidId}/feedback", handlers.Feedback(storage))
+				r.Get("/{bidId}/messages", handlers.BidThread(storage))
+				r.Post("/{bidId}/messages", handlers.PostBidMessage(storage))
+				r.Patch("/{bidId}/messages/{messageId}", handlers.EditBidMessage(storage))
+				r.Delete("/{bidId}/messages/{messageId}", handlers.DeleteBidMessage(storage))
+				r.Post("/{bidId}/rating", handlers.RateBid(storage))
+				r.Put("/{bidId}/rollback/{version}", handlers.RollbackBid(storage))
+				r.Get("/{bidId}/versions", handlers.BidVersions(storage))
+				r.Get("/{bidId}/versions/{version}", handlers.BidVersion(storage))
+				r.Get("/{bidId}/diff", handlers.BidDiff(storage))
+
+			})
+
+			r.Get("/audit", handlers.Audit(storage))
+
+			r.Get("/reputation", handlers.Reputation(storage))
+			r.Get("/reputation/ratings", handlers.Ratings(storage))
+
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Get("/", handlers.Webhooks(storage))
+				r.Post("/", handlers.CreateWebhook(storage))
+				r.Delete("/{webhookId}", handlers.DeleteWebhook(storage))
+				r.Get("/{webhookId}/deliveries", handlers.WebhookDeliveries(storage))
+				r.Post("/deliveries/{deliveryId}/redeliver", handlers.Redeliver(storage))
+			})
+
+			r.Route("/notifications", func(r chi.Router) {
+				r.Get("/", handlers.Notifications(storage))
+				r.Put("/read", handlers.ReadAllNotifications(storage))
+				r.Put("/{notificationId}/read", handlers.ReadNotification(storage))
+				r.Get("/subscriptions", handlers.Subscriptions(storage))
+				r.Put("/subscriptions/{channel}", handlers.UpdateSubscription(storage))
+			})
+
+			r.Route("/organizations/{organizationId}/roles", func(r chi.Router) {
+				r.Get("/", handlers.Roles(storage))
+				r.Put("/{member}/{role}", handlers.GrantRole(storage))
+				r.Delete("/{member}/{role}", handlers.RevokeRole(storage))
+			})
+		})
+	})
+
+	return router, nil
+
+}
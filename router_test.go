@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"zadanie/auth"
+	"zadanie/config"
+	"zadanie/memory"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+type requestCase struct {
+	Name     string            `json:"name"`
+	Method   string            `json:"method"`
+	Path     string            `json:"path"`
+	As       string            `json:"as"`
+	Headers  map[string]string `json:"headers"`
+	Body     json.RawMessage   `json:"body"`
+	Status   int               `json:"status"`
+	Response json.RawMessage   `json:"response"`
+	Save     map[string]string `json:"save"`
+}
+
+func loadRequestCases(t *testing.T, path string) []requestCase {
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var cases []requestCase
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var c requestCase
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			t.Fatalf("%s:%d: %v", path, line, err)
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return cases
+
+}
+
+func TestRouterRequests(t *testing.T) {
+
+	openapi3.SchemaErrorDetailsDisabled = true
+
+	s := memory.NewStorage()
+	if err := s.Seed("testdata/seed.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Config{RequestTimeout: 5 * time.Second}
+	cfg.Auth.Secret = []byte("router-test-secret-router-test-secret")
+	cfg.Auth.TokenTTL = time.Hour
+	cfg.Auth.UsernameCompat = true
+
+	router, err := newRouter(s, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := auth.NewIssuer(cfg.Auth.Secret, cfg.Auth.TokenTTL)
+	vars := map[string]string{}
+
+	for i, c := range loadRequestCases(t, "testdata/requests.jsonl") {
+		t.Run(fmt.Sprintf("%02d %s", i+1, c.Name), func(t *testing.T) {
+
+			var body io.Reader
+			if len(c.Body) != 0 {
+				body = strings.NewReader(expand(string(c.Body), vars))
+			}
+
+			r := httptest.NewRequest(c.Method, expand(c.Path, vars), body)
+			if body != nil {
+				r.Header.Set("Content-Type", "application/json")
+			}
+			for k, v := range c.Headers {
+				r.Header.Set(k, expand(v, vars))
+			}
+			if len(c.As) != 0 {
+				employee, err := s.Employee(r.Context(), c.As)
+				if err != nil {
+					t.Fatal(err)
+				}
+				token, err := issuer.Issue(employee)
+				if err != nil {
+					t.Fatal(err)
+				}
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != c.Status {
+				t.Fatalf("status %d, want %d: %s", w.Code, c.Status, w.Body.String())
+			}
+
+			if len(c.Response) == 0 && len(c.Save) == 0 {
+				return
+			}
+
+			var got any
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("%v: %s", err, w.Body.String())
+			}
+
+			if len(c.Response) != 0 {
+				var want any
+				if err := json.Unmarshal([]byte(expand(string(c.Response), vars)), &want); err != nil {
+					t.Fatal(err)
+				}
+				if err := contains(got, want, "$"); err != nil {
+					t.Fatalf("%v: %s", err, w.Body.String())
+				}
+			}
+
+			for name, path := range c.Save {
+				v, ok := lookup(got, path)
+				if !ok {
+					t.Fatalf("no %q in %s", path, w.Body.String())
+				}
+				vars[name] = fmt.Sprint(v)
+			}
+
+		})
+	}
+
+}
+
+func expand(s string, vars map[string]string) string {
+	for name, v := range vars {
+		s = strings.ReplaceAll(s, "{{"+name+"}}", v)
+	}
+	return s
+}
+
+func lookup(v any, path string) (any, bool) {
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+
+}
+
+// contains reports whether got has every field of want; arrays must match in length.
+func contains(got, want any, path string) error {
+
+	switch want := want.(type) {
+	case map[string]any:
+		node, ok := got.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: got %v, want object", path, got)
+		}
+		for k, v := range want {
+			field, ok := node[k]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, k)
+			}
+			if err := contains(field, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+	case []any:
+		node, ok := got.([]any)
+		if !ok || len(node) != len(want) {
+			return fmt.Errorf("%s: got %v, want %d items", path, got, len(want))
+		}
+		for i := range want {
+			if err := contains(node[i], want[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	default:
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("%s: got %v, want %v", path, got, want)
+		}
+	}
+
+	return nil
+
+}
@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+	"zadanie/model"
+)
+
+type TenderCloser interface {
+	CloseExpiredTenders(ctx context.Context, now time.Time) ([]model.Tender, error)
+}
+
+func CloseExpiredTenders(ctx context.Context, s TenderCloser, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		closed, err := s.CloseExpiredTenders(ctx, time.Now())
+		if err != nil {
+			log.Printf("close expired tenders: %s", err.Error())
+		}
+
+		for _, tender := range closed {
+			log.Printf("tender %s closed: %s", tender.Id, *tender.CloseReason)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+}
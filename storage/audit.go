@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"zadanie/model"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+)
+
+type eventTx struct {
+	pgx.Tx
+	events []model.AuditEvent
+}
+
+func (s *Storage) tx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+
+	var events []model.AuditEvent
+	err := pgx.BeginFunc(ctx, s.conn, func(tx pgx.Tx) error {
+
+		etx := &eventTx{Tx: tx}
+		if err := fn(etx); err != nil {
+			return err
+		}
+
+		events = etx.events
+		return nil
+
+	})
+	if err != nil {
+		return err
+	}
+
+	if parent, ok := s.conn.(*eventTx); ok {
+		parent.events = append(parent.events, events...)
+	} else {
+		s.bus.Publish(events...)
+	}
+
+	return nil
+
+}
+
+func atomic[T any](ctx context.Context, s *Storage, fn func(s *Storage) (T, error)) (T, error) {
+
+	var res T
+	err := s.tx(ctx, func(tx pgx.Tx) error {
+
+		var err error
+		res, err = fn(&Storage{pool: s.pool, conn: tx, bus: s.bus, hub: s.hub})
+		return err
+
+	})
+
+	return res, err
+
+}
+
+func audit(ctx context.Context, db conn, events ...model.AuditEvent) error {
+
+	var requestId *string
+	if id := middleware.GetReqID(ctx); len(id) != 0 {
+		requestId = &id
+	}
+
+	insert := `	INSERT INTO audit_event(actor_id, organization_id, entity_type, entity_id, action, before_version, after_version, request_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id, created_at;`
+
+	etx, _ := db.(*eventTx)
+	for _, e := range events {
+		if err := db.QueryRow(ctx, insert, e.ActorId, e.OrganizationId, e.EntityType, e.EntityId, e.Action,
+			e.BeforeVersion, e.AfterVersion, requestId).Scan(&e.Id, &e.CreatedAt); err != nil {
+			return err
+		}
+		if err := enqueueWebhooks(ctx, db, e); err != nil {
+			return err
+		}
+		if err := notifyChange(ctx, db, e); err != nil {
+			return err
+		}
+		if etx != nil {
+			e.RequestId = requestId
+			etx.events = append(etx.events, e)
+		}
+	}
+
+	return nil
+
+}
+
+func (s *Storage) ReadAudit(ctx context.Context, username string, filter model.AuditFilter, opts model.ListOptions) (model.Page[model.AuditEvent], error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return model.Page[model.AuditEvent]{}, err
+	}
+
+	orgId, err := s.userOrgId(ctx, userId)
+	if err != nil {
+		return model.Page[model.AuditEvent]{}, err
+	}
+
+	if !s.checkPermission(ctx, userId, orgId, model.ActionView) {
+		return model.Page[model.AuditEvent]{}, ErrNotEnoughPerm
+	}
+
+	q := query{}
+	from := `FROM audit_event WHERE ` + q.set("organization_id", orgId)
+
+	if len(filter.EntityType) != 0 {
+		from += " AND " + q.set("entity_type", filter.EntityType)
+	}
+	if filter.EntityId != nil {
+		from += " AND " + q.set("entity_id", *filter.EntityId)
+	}
+	if len(filter.Actor) != 0 {
+		actorId, err := s.userId(ctx, filter.Actor)
+		if err != nil {
+			return model.Page[model.AuditEvent]{}, err
+		}
+		from += " AND " + q.set("actor_id", actorId)
+	}
+
+	return readPage[model.AuditEvent](ctx, s, "audit_event", from, q, opts)
+
+}
+
+func mutate[T any](ctx context.Context, s *Storage, sql string, args []any, event func(T) model.AuditEvent) (T, error) {
+
+	var res T
+	err := s.tx(ctx, func(tx pgx.Tx) error {
+
+		row, err := tx.Query(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		res, err = pgx.CollectOneRow(row, pgx.RowToStructByNameLax[T])
+		if err != nil {
+			return err
+		}
+
+		return audit(ctx, tx, event(res))
+
+	})
+
+	return res, err
+
+}
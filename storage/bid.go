@@ -2,8 +2,10 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"zadanie/model"
 
 	"github.com/gofrs/uuid"
@@ -11,8 +13,15 @@ import (
 )
 
 func (s *Storage) bid(ctx context.Context, id uuid.UUID) (model.Bid, error) {
+	return s.findBid(ctx, `SELECT * FROM bid WHERE id = $1;`, id)
+}
+
+func (s *Storage) lockBid(ctx context.Context, id uuid.UUID) (model.Bid, error) {
+	return s.findBid(ctx, `SELECT * FROM bid WHERE id = $1 FOR UPDATE;`, id)
+}
+
+func (s *Storage) findBid(ctx context.Context, query string, id uuid.UUID) (model.Bid, error) {
 
-	query := `SELECT * FROM bid WHERE id = $1`
 	row, err := s.conn.Query(ctx, query, id)
 	if err != nil {
 		return model.Bid{}, err
@@ -59,110 +68,110 @@ func (s *Storage) CreateBid(ctx context.Context, b model.Bid) (model.Bid, error)
 		return model.Bid{}, ErrNotEnoughPerm
 	}
 
-	insert := `	INSERT INTO bid(name, description, status, tender_id, author_type, author_id)
-				VALUES ($1, $2, $3, $4, $5, $6)
+	if !tender.AcceptsBids(time.Now()) {
+		return model.Bid{}, ErrSubmissionClosed
+	}
+
+	if !b.ValidateTerms(tender) {
+		return model.Bid{}, ErrIncorrectTerms
+	}
+
+	outOfBudget := !tender.FitsBudget(b)
+	if outOfBudget && tender.BudgetPolicy == model.BudgetPolicyReject {
+		return model.Bid{}, ErrPriceOutOfBudget
+	}
+
+	insert := `	INSERT INTO bid(name, description, status, tender_id, author_type, author_id,
+					price, currency, delivery_days, valid_until, out_of_budget, modified_by, operation)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 				RETURNING *;`
 
-	row, err := s.conn.Query(ctx, insert, b.Name, b.Description, model.BidStatusCreated, b.TenderId, b.AuthorType, b.AuthorId)
+	authorOrgId, err := s.userOrgId(ctx, b.AuthorId)
 	if err != nil {
-		return model.Bid{}, err
+		authorOrgId = uuid.Nil
 	}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Bid])
+	args := []any{b.Name, b.Description, model.BidStatusCreated, b.TenderId, b.AuthorType, b.AuthorId,
+		b.Price, b.Currency, b.DeliveryDays, utc(b.ValidUntil), outOfBudget, b.AuthorId, model.OperationCreate}
+
+	return mutate(ctx, s, insert, args, func(created model.Bid) model.AuditEvent {
+		return model.BidEvent(model.AuditBidCreate, &b.AuthorId, authorOrgId, nil, created)
+	})
 
 }
 
-func (s *Storage) ReadMyBids(ctx context.Context, username string, limit, offset int) ([]model.Bid, error) {
+func (s *Storage) ReadMyBids(ctx context.Context, username string, opts model.ListOptions) (model.Page[model.Bid], error) {
 
 	userId, err := s.userId(ctx, username)
 	if err != nil {
-		return nil, err
+		return model.Page[model.Bid]{}, err
 	}
 
-	row, err := s.conn.Query(ctx, `SELECT * FROM bid WHERE author_id = $1 ORDER BY name ASC LIMIT $2 OFFSET $3;`, userId, limit, offset)
-	if err != nil {
-		return nil, err
-	}
+	q := query{}
+	from := `FROM bid WHERE author_id = ` + q.arg(userId)
 
-	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Bid])
+	return readPage[model.Bid](ctx, s, "bid", from, q, opts)
 
 }
 
-func (s *Storage) ReadBids(ctx context.Context, tenderId uuid.UUID, username string, limit, offset int) ([]model.Bid, error) {
+func (s *Storage) ReadBids(ctx context.Context, tenderId uuid.UUID, username string, opts model.ListOptions) (model.Page[model.Bid], error) {
 
 	if _, err := s.tender(ctx, tenderId); err != nil {
-		return nil, err
+		return model.Page[model.Bid]{}, err
 	}
 
 	userId, err := s.userId(ctx, username)
 	if err != nil {
-		return nil, err
+		return model.Page[model.Bid]{}, err
 	}
 
 	userOrgId, err := s.userOrgId(ctx, userId)
 	if err != nil {
-		return nil, err
+		return model.Page[model.Bid]{}, err
 	}
 
-	query := `	SELECT bid.*
-				FROM bid
+	q := query{}
+	from := fmt.Sprintf(`	FROM bid
 				JOIN tender ON bid.tender_id = tender.id
-				WHERE bid.tender_id = $1
+				WHERE bid.tender_id = %[1]s
 				AND ( bid.author_id IN (
 					SELECT user_id
 					FROM organization_responsible 
-					WHERE organization_id = $2)
-					OR ( tender.organization_id = $2 
-						AND bid.status = 'Published') )
-				ORDER BY bid.name ASC
-				LIMIT $3 OFFSET $4;`
+					WHERE organization_id = %[2]s)
+					OR ( tender.organization_id = %[2]s 
+						AND bid.status = 'Published') )`, q.arg(tenderId), q.arg(userOrgId))
 
-	row, err := s.conn.Query(ctx, query, tenderId, userOrgId, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-
-	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Bid])
+	return readPage[model.Bid](ctx, s, "bid", from, q, opts)
 
 }
 
-func (s *Storage) ReadBidStatus(ctx context.Context, bidId uuid.UUID, username string) (model.BidStatus, error) {
-
-	userId, err := s.userId(ctx, username)
-	if err != nil {
-		return "", err
-	}
-
-	bid, err := s.bid(ctx, bidId)
-	if err != nil {
-		return "", err
-	}
+func (s *Storage) checkBidAccess(ctx context.Context, userId uuid.UUID, bid model.Bid) error {
 
 	bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	tender, err := s.tender(ctx, bid.TenderId)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	switch {
-	case s.checkRelationToOrganization(ctx, userId, tender.OrganizationId):
+	case s.checkPermission(ctx, userId, bidOrgId, model.ActionView):
+	case s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionView):
 		if bid.Status == model.BidStatusCreated || bid.Status == model.BidStatusCanceled {
-			return "", ErrNotEnoughPerm
+			return ErrNotEnoughPerm
 		}
-	case s.checkRelationToOrganization(ctx, userId, bidOrgId):
 	default:
-		return "", ErrNotEnoughPerm
+		return ErrNotEnoughPerm
 	}
 
-	return bid.Status, nil
+	return nil
 
 }
 
-func (s *Storage) UpdateBidStatus(ctx context.Context, bidId uuid.UUID, username string, status model.BidStatus) (model.Bid, error) {
+func (s *Storage) ReadBid(ctx context.Context, bidId uuid.UUID, username string) (model.Bid, error) {
 
 	userId, err := s.userId(ctx, username)
 	if err != nil {
@@ -174,36 +183,40 @@ func (s *Storage) UpdateBidStatus(ctx context.Context, bidId uuid.UUID, username
 		return model.Bid{}, err
 	}
 
-	bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
-	if err != nil {
+	if err := s.checkBidAccess(ctx, userId, bid); err != nil {
 		return model.Bid{}, err
 	}
 
-	if !s.checkRelationToOrganization(ctx, userId, bidOrgId) {
-		return model.Bid{}, ErrNotEnoughPerm
+	return bid, nil
+
+}
+
+func (s *Storage) ReadBidVersions(ctx context.Context, bidId uuid.UUID, username string) ([]model.Bid, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return nil, err
 	}
 
-	if bid.Status == model.BidStatusCanceled {
-		return model.Bid{}, ErrStatusCantBeChanged
+	bid, err := s.bid(ctx, bidId)
+	if err != nil {
+		return nil, err
 	}
 
-	update := `	UPDATE bid 
-				SET status = $1, 
-					version = version + 1, 
-					updated_at = now()::timestamp without time zone
-				WHERE id = $2 
-				RETURNING *;`
+	if err := s.checkBidAccess(ctx, userId, bid); err != nil {
+		return nil, err
+	}
 
-	row, err := s.conn.Query(ctx, update, status, bidId)
+	row, err := s.conn.Query(ctx, `SELECT * FROM bid_archive WHERE id = $1 ORDER BY version ASC;`, bidId)
 	if err != nil {
-		return model.Bid{}, err
+		return nil, err
 	}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Bid])
+	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Bid])
 
 }
 
-func (s *Storage) UpdateBid(ctx context.Context, bidId uuid.UUID, username string, new model.Bid) (model.Bid, error) {
+func (s *Storage) ReadBidVersion(ctx context.Context, bidId uuid.UUID, version int, username string) (model.Bid, error) {
 
 	userId, err := s.userId(ctx, username)
 	if err != nil {
@@ -215,185 +228,368 @@ func (s *Storage) UpdateBid(ctx context.Context, bidId uuid.UUID, username strin
 		return model.Bid{}, err
 	}
 
-	bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
-	if err != nil {
+	if err := s.checkBidAccess(ctx, userId, bid); err != nil {
 		return model.Bid{}, err
 	}
 
-	if !s.checkRelationToOrganization(ctx, userId, bidOrgId) {
-		return model.Bid{}, ErrNotEnoughPerm
-	}
+	return s.bidVer(ctx, bidId, version)
 
-	parts := []string{}
-	if len(new.Name) != 0 {
-		parts = append(parts, fmt.Sprintf("name = '%s' ", new.Name))
-	}
-	if len(new.Description) != 0 {
-		parts = append(parts, fmt.Sprintf("description = '%s' ", new.Description))
-	}
+}
+
+func (s *Storage) UpdateBidStatus(ctx context.Context, bidId uuid.UUID, username string, status model.BidStatus, match model.Versions) (model.Bid, error) {
+	return atomic(ctx, s, func(s *Storage) (model.Bid, error) {
 
-	parts = append(parts, "version = version + 1 ")
-	parts = append(parts, "updated_at = now()::timestamp without time zone ")
+		userId, err := s.userId(ctx, username)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	update := fmt.Sprintf(`UPDATE bid SET %s WHERE id = $1 RETURNING *;`, strings.Join(parts, ","))
+		bid, err := s.lockBid(ctx, bidId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	row, err := s.conn.Query(ctx, update, bidId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
+		if err != nil {
+			return model.Bid{}, err
+		}
+
+		if !s.checkPermission(ctx, userId, bidOrgId, model.ActionEditBid) {
+			return model.Bid{}, ErrNotEnoughPerm
+		}
+
+		if !match.Match(bid.Version) {
+			return model.Bid{}, ErrVersionMismatch
+		}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Bid])
+		if bid.Status == model.BidStatusCanceled {
+			return model.Bid{}, ErrStatusCantBeChanged
+		}
 
+		update := `	UPDATE bid 
+					SET status = $1, 
+						modified_by = $2,
+						operation = $3,
+						version = version + 1, 
+						updated_at = now()::timestamp without time zone
+					WHERE id = $4 
+					RETURNING *;`
+
+		return mutate(ctx, s, update, []any{status, userId, model.OperationStatusChange, bidId}, func(updated model.Bid) model.AuditEvent {
+			return model.BidEvent(model.AuditBidStatus, &userId, bidOrgId, &bid, updated)
+		})
+
+	})
 }
 
-func (s *Storage) RollbackBid(ctx context.Context, bidId uuid.UUID, version int, username string) (model.Bid, error) {
+func (s *Storage) UpdateBid(ctx context.Context, bidId uuid.UUID, username string, new model.Bid, match model.Versions) (model.Bid, error) {
+	return atomic(ctx, s, func(s *Storage) (model.Bid, error) {
 
-	userId, err := s.userId(ctx, username)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		userId, err := s.userId(ctx, username)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	bid, err := s.bid(ctx, bidId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		bid, err := s.lockBid(ctx, bidId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	if !s.checkRelationToOrganization(ctx, userId, bidOrgId) {
-		return model.Bid{}, ErrNotEnoughPerm
-	}
+		if !s.checkPermission(ctx, userId, bidOrgId, model.ActionEditBid) {
+			return model.Bid{}, ErrNotEnoughPerm
+		}
 
-	oldBid, err := s.bidVer(ctx, bidId, version)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		if !match.Match(bid.Version) {
+			return model.Bid{}, ErrVersionMismatch
+		}
 
-	query := `	UPDATE bid
-				SET name = $1,
-					description = $2,
-					status = $3,
-					version = version + 1, 
-					updated_at = now()::timestamp without time zone
-				WHERE id = $4
-				RETURNING *;`
+		q := query{}
+		parts := []string{}
+		if len(new.Name) != 0 {
+			parts = append(parts, q.set("name", new.Name))
+		}
+		if len(new.Description) != 0 {
+			parts = append(parts, q.set("description", new.Description))
+		}
+		if new.Price != nil {
+			bid.Price = new.Price
+			parts = append(parts, q.set("price", new.Price))
+		}
+		if new.Currency != nil {
+			bid.Currency = new.Currency
+			parts = append(parts, q.set("currency", new.Currency))
+		}
+		if new.DeliveryDays != nil {
+			bid.DeliveryDays = new.DeliveryDays
+			parts = append(parts, q.set("delivery_days", new.DeliveryDays))
+		}
+		if new.ValidUntil != nil {
+			bid.ValidUntil = utc(new.ValidUntil)
+			parts = append(parts, q.set("valid_until", bid.ValidUntil))
+		}
 
-	row, err := s.conn.Query(ctx, query, oldBid.Name, oldBid.Description, oldBid.Status, bidId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		tender, err := s.tender(ctx, bid.TenderId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Bid])
+		if !bid.ValidateTerms(tender) {
+			return model.Bid{}, ErrIncorrectTerms
+		}
 
-}
+		outOfBudget := !tender.FitsBudget(bid)
+		if outOfBudget && tender.BudgetPolicy == model.BudgetPolicyReject {
+			return model.Bid{}, ErrPriceOutOfBudget
+		}
+		parts = append(parts, q.set("out_of_budget", outOfBudget))
 
-func (s *Storage) SubmitDecision(ctx context.Context, bidId uuid.UUID, decision model.BidStatus, username string) (model.Bid, error) {
+		parts = append(parts, q.set("modified_by", userId))
+		parts = append(parts, q.set("operation", model.OperationEdit))
+		parts = append(parts, "version = version + 1")
+		parts = append(parts, "updated_at = now()::timestamp without time zone")
 
-	bid, err := s.bid(ctx, bidId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		update := fmt.Sprintf(`UPDATE bid SET %s WHERE id = %s RETURNING *;`, strings.Join(parts, ", "), q.arg(bidId))
 
-	if bid.Status != model.BidStatusPublished {
-		return model.Bid{}, ErrStatusCantBeChanged
-	}
+		return mutate(ctx, s, update, q.args, func(updated model.Bid) model.AuditEvent {
+			return model.BidEvent(model.AuditBidEdit, &userId, bidOrgId, &bid, updated)
+		})
 
-	userId, err := s.userId(ctx, username)
-	if err != nil {
-		return model.Bid{}, err
-	}
+	})
+}
 
-	tender, err := s.tender(ctx, bid.TenderId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+func (s *Storage) RollbackBid(ctx context.Context, bidId uuid.UUID, version int, username string, match model.Versions) (model.Bid, error) {
+	return atomic(ctx, s, func(s *Storage) (model.Bid, error) {
 
-	if !s.checkRelationToOrganization(ctx, userId, tender.OrganizationId) {
-		return model.Bid{}, ErrNotEnoughPerm
-	}
+		userId, err := s.userId(ctx, username)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	if tender.Status == model.TenderStatusClosed {
-		return model.Bid{}, ErrTenderClosed
-	}
+		bid, err := s.lockBid(ctx, bidId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	if decision == model.BidStatusRejected {
+		bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-		update := `	UPDATE bid 
-					SET status = $1, 
+		if !s.checkPermission(ctx, userId, bidOrgId, model.ActionEditBid) {
+			return model.Bid{}, ErrNotEnoughPerm
+		}
+
+		if !match.Match(bid.Version) {
+			return model.Bid{}, ErrVersionMismatch
+		}
+
+		oldBid, err := s.bidVer(ctx, bidId, version)
+		if err != nil {
+			return model.Bid{}, err
+		}
+
+		query := `	UPDATE bid
+					SET name = $1,
+						description = $2,
+						status = $3,
+						price = $4,
+						currency = $5,
+						delivery_days = $6,
+						valid_until = $7,
+						out_of_budget = $8,
+						modified_by = $9,
+						operation = $10,
 						version = version + 1, 
 						updated_at = now()::timestamp without time zone
-					WHERE id = $2 
+					WHERE id = $11
 					RETURNING *;`
 
-		row, err := s.conn.Query(ctx, update, model.BidStatusRejected, bidId)
+		args := []any{oldBid.Name, oldBid.Description, oldBid.Status,
+			oldBid.Price, oldBid.Currency, oldBid.DeliveryDays, oldBid.ValidUntil, oldBid.OutOfBudget, userId, model.OperationRollback, bidId}
+
+		return mutate(ctx, s, query, args, func(updated model.Bid) model.AuditEvent {
+			return model.BidEvent(model.AuditBidRollback, &userId, bidOrgId, &bid, updated)
+		})
+
+	})
+}
+
+func (s *Storage) SubmitDecision(ctx context.Context, bidId uuid.UUID, decision model.BidStatus, username string) (model.Bid, error) {
+	return atomic(ctx, s, func(s *Storage) (model.Bid, error) {
+
+		userId, err := s.userId(ctx, username)
 		if err != nil {
 			return model.Bid{}, err
 		}
 
-		return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Bid])
+		unlocked, err := s.bid(ctx, bidId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	}
+		tender, err := s.lockTender(ctx, unlocked.TenderId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	if _, err := s.conn.Exec(ctx, `INSERT INTO bid_approved_decision(bid_id, user_id) VALUES ($1, $2);`, bidId, userId); err != nil {
-		return model.Bid{}, err
-	}
+		bid, err := s.lockBid(ctx, bidId)
+		if err != nil {
+			return model.Bid{}, err
+		}
 
-	count := 0
-	if err := s.conn.QueryRow(ctx, `SELECT COUNT(*) FROM bid_approved_decision WHERE bid_id = $1;`, bidId).Scan(&count); err != nil {
-		return model.Bid{}, err
-	}
+		if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionDecideBid) {
+			return model.Bid{}, ErrNotEnoughPerm
+		}
 
-	q, err := s.quorum(ctx, tender.OrganizationId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		var previous model.BidStatus
+		query := `SELECT decision FROM bid_decision WHERE bid_id = $1 AND user_id = $2;`
+		if err := s.conn.QueryRow(ctx, query, bidId, userId).Scan(&previous); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return model.Bid{}, err
+		}
 
-	if count < q {
-		return bid, nil
-	}
+		if previous == decision {
+			return bid, nil
+		}
 
-	tx, err := s.conn.Begin(ctx)
-	if err != nil {
-		return model.Bid{}, err
-	}
-	defer tx.Rollback(ctx)
+		if len(previous) != 0 {
+			return model.Bid{}, ErrDecisionSubmitted
+		}
 
-	updateBid := `	UPDATE bid 
-					SET status = $1, 
-						version = version + 1, 
-						updated_at = now()::timestamp without time zone
-					WHERE id = $2 
-					RETURNING *;`
+		awarded := 0
+		query = `SELECT COUNT(*) FROM bid WHERE tender_id = $1 AND id <> $2 AND status = $3;`
+		if err := s.conn.QueryRow(ctx, query, tender.Id, bidId, model.BidStatusApproved).Scan(&awarded); err != nil {
+			return model.Bid{}, err
+		}
 
-	row, err := tx.Query(ctx, updateBid, model.BidStatusApproved, bidId)
-	if err != nil {
-		return model.Bid{}, err
-	}
+		if awarded >= tender.Awards() {
+			return model.Bid{}, ErrBidAwarded
+		}
 
-	updBid, err := pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Bid])
-	if err != nil {
-		return model.Bid{}, err
-	}
+		if bid.Status != model.BidStatusPublished {
+			return model.Bid{}, ErrStatusCantBeChanged
+		}
 
-	updateTender := `	UPDATE tender 
+		if tender.Status == model.TenderStatusClosed {
+			return model.Bid{}, ErrTenderClosed
+		}
+
+		voters, err := s.voters(ctx, tender.OrganizationId)
+		if err != nil {
+			return model.Bid{}, err
+		}
+
+		if _, err := s.conn.Exec(ctx, `INSERT INTO bid_decision(bid_id, user_id, decision) VALUES ($1, $2, $3);`, bidId, userId, decision); err != nil {
+			return model.Bid{}, err
+		}
+
+		count := 0
+		query = `SELECT COUNT(*) FROM bid_decision WHERE bid_id = $1 AND decision = $2;`
+		if err := s.conn.QueryRow(ctx, query, bidId, decision).Scan(&count); err != nil {
+			return model.Bid{}, err
+		}
+
+		updateBid := `	UPDATE bid 
 						SET status = $1, 
+							modified_by = $2,
+							operation = $3,
 							version = version + 1, 
 							updated_at = now()::timestamp without time zone
-						WHERE id = $2;`
+						WHERE id = $4 
+						RETURNING *;`
 
-	if _, err = tx.Exec(ctx, updateTender, model.TenderStatusClosed, updBid.TenderId); err != nil {
-		return model.Bid{}, err
+		if decision == model.BidStatusRejected {
+			if count < tender.RequiredRejections(len(voters)) {
+				return bid, audit(ctx, s.conn, model.BidEvent(model.AuditBidReject, &userId, tender.OrganizationId, &bid, bid))
+			}
+
+			return mutate(ctx, s, updateBid, []any{model.BidStatusRejected, userId, model.OperationDecision, bidId}, func(updated model.Bid) model.AuditEvent {
+				return model.BidEvent(model.AuditBidReject, &userId, tender.OrganizationId, &bid, updated)
+			})
+		}
+
+		if count < tender.RequiredApprovals(len(voters)) {
+			return bid, audit(ctx, s.conn, model.BidEvent(model.AuditBidApprove, &userId, tender.OrganizationId, &bid, bid))
+		}
+
+		res, err := mutate(ctx, s, updateBid, []any{model.BidStatusApproved, userId, model.OperationDecision, bidId}, func(updated model.Bid) model.AuditEvent {
+			return model.BidEvent(model.AuditBidApprove, &userId, tender.OrganizationId, &bid, updated)
+		})
+		if err != nil {
+			return model.Bid{}, err
+		}
+
+		if awarded+1 < tender.Awards() {
+			return res, nil
+		}
+
+		updateTender := `	UPDATE tender 
+							SET status = $1, 
+								close_reason = $2,
+								modified_by = $3,
+								operation = $4,
+								version = version + 1, 
+								updated_at = now()::timestamp without time zone
+							WHERE id = $5
+							RETURNING *;`
+
+		args := []any{model.TenderStatusClosed, model.TenderCloseReasonAwarded, userId, model.OperationDecision, tender.Id}
+		closed, err := mutate(ctx, s, updateTender, args, func(closed model.Tender) model.AuditEvent {
+			return model.TenderEvent(model.AuditTenderClose, &userId, &tender, closed)
+		})
+		if err != nil {
+			return model.Bid{}, err
+		}
+
+		if err := s.rejectOpenBids(ctx, closed, &userId); err != nil {
+			return model.Bid{}, err
+		}
+
+		return res, nil
+
+	})
+}
+
+func (s *Storage) rejectOpenBids(ctx context.Context, tender model.Tender, actorId *uuid.UUID) error {
+
+	update := `	UPDATE bid
+				SET status = $1,
+					modified_by = $2,
+					operation = $3,
+					version = version + 1,
+					updated_at = now()::timestamp without time zone
+				WHERE tender_id = $4
+				AND status = $5
+				RETURNING *;`
+
+	row, err := s.conn.Query(ctx, update, model.BidStatusRejected, actorId, model.OperationClose, tender.Id, model.BidStatusPublished)
+	if err != nil {
+		return err
 	}
 
-	err = tx.Commit(ctx)
+	rejected, err := pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Bid])
 	if err != nil {
-		return model.Bid{}, err
+		return err
+	}
+
+	insert := `INSERT INTO bid_feedback(bid_id, tender_id, description, side) VALUES ($1, $2, $3, $4);`
+	events := make([]model.AuditEvent, 0, len(rejected))
+	for _, bid := range rejected {
+		if _, err := s.conn.Exec(ctx, insert, bid.Id, tender.Id, tender.RejectionFeedback(), model.FeedbackSideSystem); err != nil {
+			return err
+		}
+
+		before := bid
+		before.Version--
+		events = append(events, model.BidEvent(model.AuditBidReject, actorId, tender.OrganizationId, &before, bid))
 	}
 
-	return updBid, nil
+	return audit(ctx, s.conn, events...)
+
 }
 
 func (s *Storage) Feedback(ctx context.Context, bidId uuid.UUID, feedback string, username string) (model.Bid, error) {
@@ -413,7 +609,7 @@ func (s *Storage) Feedback(ctx context.Context, bidId uuid.UUID, feedback string
 		return model.Bid{}, err
 	}
 
-	if !s.checkRelationToOrganization(ctx, userId, tender.OrganizationId) {
+	if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionFeedback) {
 		return model.Bid{}, ErrNotEnoughPerm
 	}
 
@@ -421,47 +617,96 @@ func (s *Storage) Feedback(ctx context.Context, bidId uuid.UUID, feedback string
 		return model.Bid{}, ErrNotEnoughPerm
 	}
 
-	query := `INSERT INTO bid_feedback(bid_id, tender_id, user_id, description) VALUES ($1, $2, $3, $4);`
-	if _, err := s.conn.Exec(ctx, query, bidId, tender.Id, userId, feedback); err != nil {
+	query := `INSERT INTO bid_feedback(bid_id, tender_id, user_id, description) VALUES ($1, $2, $3, $4) RETURNING *;`
+	args := []any{bidId, tender.Id, userId, feedback}
+
+	_, err = mutate(ctx, s, query, args, func(f model.BidFeedback) model.AuditEvent {
+		return model.BidEvent(model.AuditBidFeedback, &userId, tender.OrganizationId, &bid, bid).About(f)
+	})
+	if err != nil {
 		return model.Bid{}, err
 	}
 
 	return bid, nil
 }
 
-func (s *Storage) BidReviews(ctx context.Context, tenderId uuid.UUID, authorUsername, requesterUsername string, limit, offset int) ([]model.BidFeedback, error) {
+func (s *Storage) BidReviews(ctx context.Context, tenderId uuid.UUID, authorUsername, requesterUsername string, opts model.ListOptions) (model.Page[model.BidFeedback], error) {
 
 	tender, err := s.tender(ctx, tenderId)
 	if err != nil {
-		return nil, err
+		return model.Page[model.BidFeedback]{}, err
 	}
 
 	authorId, err := s.userId(ctx, authorUsername)
 	if err != nil {
-		return nil, err
+		return model.Page[model.BidFeedback]{}, err
 	}
 
 	requesterId, err := s.userId(ctx, requesterUsername)
 	if err != nil {
-		return nil, err
+		return model.Page[model.BidFeedback]{}, err
 	}
 
-	if !s.checkRelationToOrganization(ctx, requesterId, tender.OrganizationId) {
-		return nil, ErrNotEnoughPerm
+	if !s.checkPermission(ctx, requesterId, tender.OrganizationId, model.ActionView) {
+		return model.Page[model.BidFeedback]{}, ErrNotEnoughPerm
 	}
 
-	query := `	SELECT * 
-				FROM bid_feedback
-				WHERE tender_id = $1
+	q := query{}
+	from := fmt.Sprintf(`	FROM bid_feedback
+				WHERE tender_id = %s
+				AND side <> 'Author'
+				AND deleted_at IS NULL
 				AND bid_id IN (
 					SELECT id
 					FROM bid
-					WHERE author_id = $2);`
-	row, err := s.conn.Query(ctx, query, tenderId, authorId)
+					WHERE author_id = %s)`, q.arg(tenderId), q.arg(authorId))
+
+	return readPage[model.BidFeedback](ctx, s, "bid_feedback", from, q, opts)
+
+}
+
+func (s *Storage) ReadBidDecisions(ctx context.Context, bidId uuid.UUID, username string) (model.DecisionTally, error) {
+
+	userId, err := s.userId(ctx, username)
 	if err != nil {
-		return nil, err
+		return model.DecisionTally{}, err
+	}
+
+	bid, err := s.bid(ctx, bidId)
+	if err != nil {
+		return model.DecisionTally{}, err
+	}
+
+	tender, err := s.tender(ctx, bid.TenderId)
+	if err != nil {
+		return model.DecisionTally{}, err
+	}
+
+	if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionView) {
+		return model.DecisionTally{}, ErrNotEnoughPerm
+	}
+
+	query := `	SELECT d.bid_id, d.user_id, e.username, d.decision, d.created_at
+				FROM bid_decision d
+				JOIN employee e ON e.id = d.user_id
+				WHERE d.bid_id = $1
+				ORDER BY d.created_at ASC, e.username ASC;`
+
+	row, err := s.conn.Query(ctx, query, bidId)
+	if err != nil {
+		return model.DecisionTally{}, err
+	}
+
+	decisions, err := pgx.CollectRows(row, pgx.RowToStructByNameLax[model.BidDecision])
+	if err != nil {
+		return model.DecisionTally{}, err
+	}
+
+	voters, err := s.voters(ctx, tender.OrganizationId)
+	if err != nil {
+		return model.DecisionTally{}, err
 	}
 
-	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.BidFeedback])
+	return model.NewDecisionTally(tender, decisions, voters), nil
 
 }
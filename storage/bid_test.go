@@ -0,0 +1,242 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"zadanie/config"
+	"zadanie/model"
+	"zadanie/storage"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func newTestStorage(t *testing.T) (*storage.Storage, *pgxpool.Pool) {
+
+	conn := os.Getenv("POSTGRES_TEST_CONN")
+	if len(conn) == 0 {
+		t.Skip("POSTGRES_TEST_CONN is not set")
+	}
+
+	ctx := context.Background()
+
+	s, err := storage.NewStorage(ctx, config.Postgres{ConnString: conn, MaxConns: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+
+	pool, err := pgxpool.New(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	return s, pool
+
+}
+
+func addEmployee(t *testing.T, pool *pgxpool.Pool, username string) uuid.UUID {
+
+	id := uuid.Must(uuid.NewV4())
+	if _, err := pool.Exec(context.Background(), `INSERT INTO employee (id, username) VALUES ($1, $2);`, id, username); err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+
+}
+
+func addOrganization(t *testing.T, pool *pgxpool.Pool, name string, members ...uuid.UUID) uuid.UUID {
+
+	ctx := context.Background()
+
+	id := uuid.Must(uuid.NewV4())
+	if _, err := pool.Exec(ctx, `INSERT INTO organization (id, name) VALUES ($1, $2);`, id, name); err != nil {
+		t.Fatal(err)
+	}
+	for _, userId := range members {
+		if _, err := pool.Exec(ctx, `INSERT INTO organization_responsible (organization_id, user_id) VALUES ($1, $2);`, id, userId); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return id
+
+}
+
+func TestConcurrentApprovals(t *testing.T) {
+
+	s, pool := newTestStorage(t)
+	ctx := context.Background()
+
+	prefix := uuid.Must(uuid.NewV4()).String()[:8]
+	owner := prefix + "-owner"
+
+	members := []uuid.UUID{addEmployee(t, pool, owner)}
+	approvers := []string{}
+	for i := range 8 {
+		username := fmt.Sprintf("%s-approver%d", prefix, i)
+		members = append(members, addEmployee(t, pool, username))
+		approvers = append(approvers, username)
+	}
+	org := addOrganization(t, pool, prefix+" org", members...)
+
+	for _, username := range approvers {
+		if _, err := s.GrantRole(ctx, org, owner, username, model.RoleApprover); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	author := prefix + "-author"
+	authorId := addEmployee(t, pool, author)
+	addOrganization(t, pool, prefix+" author org", authorId)
+
+	tender, err := s.CreateTender(ctx, model.Tender{
+		Name:           "t",
+		Description:    "d",
+		ServiceType:    model.TenderServiceTypeDelivery,
+		OrganizationId: org,
+		ApprovalRule:   model.ApprovalRuleQuorum,
+	}, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateTenderStatus(ctx, tender.Id, owner, model.TenderStatusPublished, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	bid, err := s.CreateBid(ctx, model.Bid{
+		Name:        "b",
+		Description: "d",
+		TenderId:    tender.Id,
+		AuthorType:  model.BidAuthorTypeUser,
+		AuthorId:    authorId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bid, err = s.UpdateBidStatus(ctx, bid.Id, author, model.BidStatusPublished, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	const repeats = 3
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([][repeats]error, len(approvers))
+
+	for i, username := range approvers {
+		for j := range repeats {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, errs[i][j] = s.SubmitDecision(ctx, bid.Id, model.BidStatusApproved, username)
+			}()
+		}
+	}
+
+	close(start)
+	wg.Wait()
+
+	voted := 0
+	for i, results := range errs {
+		for _, err := range results {
+			if err != nil && !errors.Is(err, storage.ErrStatusCantBeChanged) && !errors.Is(err, storage.ErrTenderClosed) {
+				t.Fatalf("%s: %v", approvers[i], err)
+			}
+			if (err == nil) != (results[0] == nil) {
+				t.Fatalf("%s: repeated decision gave %v and %v", approvers[i], results[0], err)
+			}
+		}
+		if results[0] == nil {
+			voted++
+		}
+	}
+
+	tally, err := s.ReadBidDecisions(ctx, bid.Id, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tally.Approvals != tally.RequiredApprovals || len(tally.Decisions) != tally.RequiredApprovals || voted != tally.RequiredApprovals {
+		t.Fatalf("%d approvals from %d voters, %d recorded, want exactly %d", tally.Approvals, voted, len(tally.Decisions), tally.RequiredApprovals)
+	}
+
+	versions, err := s.ReadBidVersions(ctx, bid.Id, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved := 0
+	for _, v := range versions {
+		if v.Status == model.BidStatusApproved {
+			approved++
+		}
+	}
+	if approved != 1 || versions[len(versions)-1].Version != bid.Version+1 {
+		t.Fatalf("bid approved in %d versions, last version %d", approved, versions[len(versions)-1].Version)
+	}
+
+	page, err := s.ReadAudit(ctx, owner, model.AuditFilter{EntityType: model.EntityTender, EntityId: &tender.Id}, model.ListOptions{Sort: "created_at", Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closes := 0
+	for _, e := range page.Items {
+		if e.Action == model.AuditTenderClose {
+			closes++
+		}
+	}
+	if closes != 1 {
+		t.Fatalf("tender closed %d times", closes)
+	}
+
+}
+
+func TestDraftBidAudit(t *testing.T) {
+
+	s, pool := newTestStorage(t)
+	ctx := context.Background()
+
+	prefix := uuid.Must(uuid.NewV4()).String()[:8]
+	owner := prefix + "-owner"
+	org := addOrganization(t, pool, prefix+" org", addEmployee(t, pool, owner))
+	author := addEmployee(t, pool, prefix+"-author")
+
+	tender, err := s.CreateTender(ctx, model.Tender{
+		Name:           "t",
+		Description:    "d",
+		ServiceType:    model.TenderServiceTypeDelivery,
+		OrganizationId: org,
+	}, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateTenderStatus(ctx, tender.Id, owner, model.TenderStatusPublished, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	bid, err := s.CreateBid(ctx, model.Bid{
+		Name:        "b",
+		Description: "d",
+		TenderId:    tender.Id,
+		AuthorType:  model.BidAuthorTypeUser,
+		AuthorId:    author,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := s.ReadAudit(ctx, owner, model.AuditFilter{EntityType: model.EntityBid, EntityId: &bid.Id}, model.ListOptions{Sort: "created_at", Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 0 {
+		t.Fatalf("tender organization sees %d events of a draft bid", len(page.Items))
+	}
+
+}
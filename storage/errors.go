@@ -9,3 +9,26 @@ var ErrBidNotFound = errors.New("bid wasn't found")
 var ErrVersionNotFound = errors.New("version wasn't found")
 var ErrStatusCantBeChanged = errors.New("status cannot be changed")
 var ErrTenderClosed = errors.New("tender has already been closed")
+var ErrOrganizationNotFound = errors.New("user isn't responsible for any organization")
+var ErrMigrationName = errors.New("incorrect migration file name")
+var ErrMigrationIrreversible = errors.New("migration has no down script")
+var ErrNothingToMigrate = errors.New("no applied migrations")
+var ErrNotMember = errors.New("user isn't responsible for the organization")
+var ErrRoleNotFound = errors.New("role wasn't found")
+var ErrLastOwner = errors.New("organization must keep at least one owner")
+var ErrIncorrectDeadline = errors.New("deadlines must be in the future and the decision deadline must not precede the submission deadline")
+var ErrSubmissionClosed = errors.New("tender no longer accepts bids")
+var ErrIncorrectBudget = errors.New("budget needs a currency and a non-negative range")
+var ErrIncorrectPolicy = errors.New("approval threshold must match the approval rule")
+var ErrIncorrectTerms = errors.New("price needs a currency, terms must be non-negative and validUntil must not pass the decision deadline")
+var ErrPriceOutOfBudget = errors.New("price is outside the tender budget")
+var ErrVersionMismatch = errors.New("version has changed")
+var ErrBidAwarded = errors.New("another bid has already won the tender")
+var ErrMessageNotFound = errors.New("message wasn't found")
+var ErrMessageLocked = errors.New("message can no longer be changed")
+var ErrBidNotRatable = errors.New("only approved bids of closed tenders can be rated")
+var ErrBidRated = errors.New("bid has already been rated")
+var ErrNotificationNotFound = errors.New("notification wasn't found")
+var ErrWebhookNotFound = errors.New("webhook wasn't found")
+var ErrDeliveryNotFound = errors.New("delivery wasn't found")
+var ErrDecisionSubmitted = errors.New("decision has already been submitted")
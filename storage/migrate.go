@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+	"zadanie/config"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const migrationsLock = 6105
+
+type Migration struct {
+	Version   int
+	Name      string
+	Up        string
+	Down      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	conn *pgxpool.Pool
+}
+
+func NewMigrator(ctx context.Context, cfg config.Postgres) (*Migrator, error) {
+
+	conn, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		conn: conn,
+	}, nil
+
+}
+
+func (m *Migrator) Close() {
+	m.conn.Close()
+}
+
+func migrations() ([]Migration, error) {
+
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+
+		parts := migrationName.FindStringSubmatch(entry.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationName, entry.Name())
+		}
+
+		version, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = m
+		}
+		if m.Name != parts[2] {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationName, entry.Name())
+		}
+
+		if parts[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	res := []Migration{}
+	for _, m := range byVersion {
+		res = append(res, *m)
+	}
+
+	slices.SortFunc(res, func(a, b Migration) int {
+		return a.Version - b.Version
+	})
+
+	return res, nil
+
+}
+
+func (m *Migrator) init(ctx context.Context) error {
+
+	query := `	CREATE TABLE IF NOT EXISTS schema_migrations (
+					version INTEGER PRIMARY KEY,
+					name TEXT NOT NULL,
+					applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+				);`
+
+	_, err := m.conn.Exec(ctx, query)
+	return err
+
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+
+	all, err := migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int]time.Time{}
+	row, err := m.conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations;`)
+	if err != nil {
+		return nil, err
+	}
+
+	version, appliedAt := 0, time.Time{}
+	if _, err := pgx.ForEachRow(row, []any{&version, &appliedAt}, func() error {
+		applied[version] = appliedAt
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for i := range all {
+		if t, ok := applied[all[i].Version]; ok {
+			all[i].AppliedAt = &t
+		}
+	}
+
+	return all, nil
+
+}
+
+func (m *Migrator) Up(ctx context.Context) error {
+
+	all, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range all {
+		if migration.AppliedAt != nil {
+			continue
+		}
+
+		if err := m.apply(ctx, migration, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+func (m *Migrator) Down(ctx context.Context) error {
+
+	all, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].AppliedAt == nil {
+			continue
+		}
+
+		if len(all[i].Down) == 0 {
+			return fmt.Errorf("%w: %04d_%s", ErrMigrationIrreversible, all[i].Version, all[i].Name)
+		}
+
+		return m.apply(ctx, all[i], false)
+	}
+
+	return ErrNothingToMigrate
+
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration, up bool) error {
+
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1);`, migrationsLock); err != nil {
+		return err
+	}
+
+	applied := false
+	query := `SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1);`
+	if err := tx.QueryRow(ctx, query, migration.Version).Scan(&applied); err != nil {
+		return err
+	}
+
+	if applied == up {
+		return nil
+	}
+
+	body, record, args := migration.Up, `INSERT INTO schema_migrations(version, name) VALUES ($1, $2);`, []any{migration.Version, migration.Name}
+	if !up {
+		body, record, args = migration.Down, `DELETE FROM schema_migrations WHERE version = $1;`, []any{migration.Version}
+	}
+
+	if _, err := tx.Exec(ctx, body); err != nil {
+		return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if _, err := tx.Exec(ctx, record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+
+}
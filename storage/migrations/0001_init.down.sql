@@ -0,0 +1,27 @@
+DROP TABLE IF EXISTS bid_approved_decision;
+
+DROP TABLE IF EXISTS bid_feedback;
+
+DROP TRIGGER IF EXISTS trg_archive_bid ON bid;
+
+DROP FUNCTION IF EXISTS archive_bid();
+
+DROP TABLE IF EXISTS bid_archive;
+
+DROP TABLE IF EXISTS bid;
+
+DROP TRIGGER IF EXISTS trg_archive_tender ON tender;
+
+DROP FUNCTION IF EXISTS archive_tender();
+
+DROP TABLE IF EXISTS tender_archive;
+
+DROP TABLE IF EXISTS tender;
+
+DROP TYPE IF EXISTS bid_status;
+
+DROP TYPE IF EXISTS author_type;
+
+DROP TYPE IF EXISTS service_type;
+
+DROP TYPE IF EXISTS tender_status;
@@ -1,30 +1,54 @@
-CREATE TYPE tender_status AS ENUM (
-    'Created',
-    'Published',
-    'Closed'
-);
-
-
-CREATE TYPE service_type AS ENUM (
-    'Construction',
-    'Delivery',
-    'Manufacture'
-);
-
-
-CREATE TYPE author_type AS ENUM (
-    'Organization',
-    'User'
-);
+DO $$
+BEGIN
+    CREATE TYPE tender_status AS ENUM (
+        'Created',
+        'Published',
+        'Closed'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+DO $$
+BEGIN
+    CREATE TYPE service_type AS ENUM (
+        'Construction',
+        'Delivery',
+        'Manufacture'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+DO $$
+BEGIN
+    CREATE TYPE author_type AS ENUM (
+        'Organization',
+        'User'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
 
 
-CREATE TYPE bid_status AS ENUM (
-    'Created',
-    'Published',
-	'Canceled',
-	'Approved',
-	'Rejected'
-);
+DO $$
+BEGIN
+    CREATE TYPE bid_status AS ENUM (
+        'Created',
+        'Published',
+    	'Canceled',
+    	'Approved',
+    	'Rejected'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
 
 
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
@@ -133,5 +157,3 @@ CREATE TABLE IF NOT EXISTS bid_approved_decision (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	PRIMARY KEY(bid_id, user_id)
 );
-
-
@@ -0,0 +1 @@
+ALTER TABLE bid_feedback DROP COLUMN IF EXISTS tender_id;
@@ -0,0 +1,8 @@
+ALTER TABLE bid_feedback
+ADD COLUMN IF NOT EXISTS tender_id UUID REFERENCES tender(id) ON DELETE CASCADE;
+
+UPDATE bid_feedback
+SET tender_id = bid.tender_id
+FROM bid
+WHERE bid_feedback.bid_id = bid.id
+AND bid_feedback.tender_id IS NULL;
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS trg_revoke_roles ON organization_responsible;
+
+DROP FUNCTION IF EXISTS revoke_roles();
+
+DROP TRIGGER IF EXISTS trg_grant_default_role ON organization_responsible;
+
+DROP FUNCTION IF EXISTS grant_default_role();
+
+DROP TABLE IF EXISTS organization_role;
+
+DROP TYPE IF EXISTS organization_role;
@@ -0,0 +1,68 @@
+DO $$
+BEGIN
+    CREATE TYPE organization_role AS ENUM (
+        'Owner',
+        'Editor',
+        'Approver',
+        'Viewer'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+CREATE TABLE IF NOT EXISTS organization_role (
+	organization_id UUID REFERENCES organization(id) ON DELETE CASCADE,
+	user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+	role organization_role NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY(organization_id, user_id, role)
+);
+
+
+INSERT INTO organization_role (organization_id, user_id, role)
+SELECT organization_id, user_id, 'Owner'
+FROM organization_responsible
+ON CONFLICT DO NOTHING;
+
+
+CREATE OR REPLACE FUNCTION grant_default_role()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO organization_role (organization_id, user_id, role)
+    VALUES (new.organization_id, new.user_id,
+        CASE WHEN EXISTS (
+            SELECT 1
+            FROM organization_role
+            WHERE organization_id = new.organization_id
+            AND role = 'Owner')
+        THEN 'Viewer'::organization_role
+        ELSE 'Owner'::organization_role END)
+    ON CONFLICT DO NOTHING;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+
+CREATE OR REPLACE TRIGGER trg_grant_default_role
+AFTER INSERT ON organization_responsible
+FOR EACH ROW
+EXECUTE PROCEDURE grant_default_role();
+
+
+CREATE OR REPLACE FUNCTION revoke_roles()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM organization_role
+    WHERE organization_id = old.organization_id
+    AND user_id = old.user_id;
+    RETURN old;
+END;
+$$ LANGUAGE plpgsql;
+
+
+CREATE OR REPLACE TRIGGER trg_revoke_roles
+AFTER DELETE ON organization_responsible
+FOR EACH ROW
+EXECUTE PROCEDURE revoke_roles();
@@ -0,0 +1,20 @@
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS tender_deadline_idx;
+
+ALTER TABLE tender_archive
+DROP COLUMN IF EXISTS submission_deadline,
+DROP COLUMN IF EXISTS decision_deadline,
+DROP COLUMN IF EXISTS close_reason;
+
+ALTER TABLE tender
+DROP COLUMN IF EXISTS submission_deadline,
+DROP COLUMN IF EXISTS decision_deadline,
+DROP COLUMN IF EXISTS close_reason;
@@ -0,0 +1,27 @@
+ALTER TABLE tender
+ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMP,
+ADD COLUMN IF NOT EXISTS decision_deadline TIMESTAMP,
+ADD COLUMN IF NOT EXISTS close_reason TEXT;
+
+
+ALTER TABLE tender_archive
+ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMP,
+ADD COLUMN IF NOT EXISTS decision_deadline TIMESTAMP,
+ADD COLUMN IF NOT EXISTS close_reason TEXT;
+
+
+CREATE INDEX IF NOT EXISTS tender_deadline_idx
+ON tender (COALESCE(decision_deadline, submission_deadline))
+WHERE status <> 'Closed';
+
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
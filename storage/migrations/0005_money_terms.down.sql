@@ -0,0 +1,47 @@
+CREATE OR REPLACE FUNCTION archive_bid()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO bid_archive (id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at)
+    VALUES (new.id, new.name, new.description, new.status, new.tender_id, new.author_type, new.author_id, new.version, new.created_at, new.updated_at);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE bid_archive
+DROP COLUMN IF EXISTS price,
+DROP COLUMN IF EXISTS currency,
+DROP COLUMN IF EXISTS delivery_days,
+DROP COLUMN IF EXISTS valid_until,
+DROP COLUMN IF EXISTS out_of_budget;
+
+ALTER TABLE bid
+DROP COLUMN IF EXISTS price,
+DROP COLUMN IF EXISTS currency,
+DROP COLUMN IF EXISTS delivery_days,
+DROP COLUMN IF EXISTS valid_until,
+DROP COLUMN IF EXISTS out_of_budget;
+
+ALTER TABLE tender_archive
+DROP COLUMN IF EXISTS budget_min,
+DROP COLUMN IF EXISTS budget_max,
+DROP COLUMN IF EXISTS currency,
+DROP COLUMN IF EXISTS budget_policy;
+
+ALTER TABLE tender
+DROP COLUMN IF EXISTS budget_min,
+DROP COLUMN IF EXISTS budget_max,
+DROP COLUMN IF EXISTS currency,
+DROP COLUMN IF EXISTS budget_policy;
+
+DROP TYPE IF EXISTS budget_policy;
@@ -0,0 +1,64 @@
+DO $$
+BEGIN
+    CREATE TYPE budget_policy AS ENUM (
+        'Warn',
+        'Reject'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+ALTER TABLE tender
+ADD COLUMN IF NOT EXISTS budget_min NUMERIC(16, 2),
+ADD COLUMN IF NOT EXISTS budget_max NUMERIC(16, 2),
+ADD COLUMN IF NOT EXISTS currency CHAR(3),
+ADD COLUMN IF NOT EXISTS budget_policy budget_policy NOT NULL DEFAULT 'Warn';
+
+
+ALTER TABLE tender_archive
+ADD COLUMN IF NOT EXISTS budget_min NUMERIC(16, 2),
+ADD COLUMN IF NOT EXISTS budget_max NUMERIC(16, 2),
+ADD COLUMN IF NOT EXISTS currency CHAR(3),
+ADD COLUMN IF NOT EXISTS budget_policy budget_policy NOT NULL DEFAULT 'Warn';
+
+
+ALTER TABLE bid
+ADD COLUMN IF NOT EXISTS price NUMERIC(16, 2),
+ADD COLUMN IF NOT EXISTS currency CHAR(3),
+ADD COLUMN IF NOT EXISTS delivery_days INTEGER,
+ADD COLUMN IF NOT EXISTS valid_until TIMESTAMP,
+ADD COLUMN IF NOT EXISTS out_of_budget BOOLEAN NOT NULL DEFAULT false;
+
+
+ALTER TABLE bid_archive
+ADD COLUMN IF NOT EXISTS price NUMERIC(16, 2),
+ADD COLUMN IF NOT EXISTS currency CHAR(3),
+ADD COLUMN IF NOT EXISTS delivery_days INTEGER,
+ADD COLUMN IF NOT EXISTS valid_until TIMESTAMP,
+ADD COLUMN IF NOT EXISTS out_of_budget BOOLEAN NOT NULL DEFAULT false;
+
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason, budget_min, budget_max, currency, budget_policy)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason, new.budget_min, new.budget_max, new.currency, new.budget_policy);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+
+CREATE OR REPLACE FUNCTION archive_bid()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO bid_archive (id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at,
+        price, currency, delivery_days, valid_until, out_of_budget)
+    VALUES (new.id, new.name, new.description, new.status, new.tender_id, new.author_type, new.author_id, new.version, new.created_at, new.updated_at,
+        new.price, new.currency, new.delivery_days, new.valid_until, new.out_of_budget);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tender_search_idx;
+
+DROP FUNCTION IF EXISTS tender_document(TEXT, TEXT);
@@ -0,0 +1,10 @@
+CREATE OR REPLACE FUNCTION tender_document(name TEXT, description TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('russian', COALESCE(name, '')), 'A') ||
+           setweight(to_tsvector('russian', COALESCE(description, '')), 'B');
+$$ LANGUAGE sql IMMUTABLE;
+
+
+CREATE INDEX IF NOT EXISTS tender_search_idx
+ON tender USING GIN (tender_document(name, description))
+WHERE status = 'Published';
@@ -0,0 +1,39 @@
+CREATE OR REPLACE FUNCTION archive_bid()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO bid_archive (id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at,
+        price, currency, delivery_days, valid_until, out_of_budget)
+    VALUES (new.id, new.name, new.description, new.status, new.tender_id, new.author_type, new.author_id, new.version, new.created_at, new.updated_at,
+        new.price, new.currency, new.delivery_days, new.valid_until, new.out_of_budget);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason, budget_min, budget_max, currency, budget_policy)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason, new.budget_min, new.budget_max, new.currency, new.budget_policy);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE bid_archive
+DROP COLUMN IF EXISTS modified_by,
+DROP COLUMN IF EXISTS operation;
+
+ALTER TABLE bid
+DROP COLUMN IF EXISTS modified_by,
+DROP COLUMN IF EXISTS operation;
+
+ALTER TABLE tender_archive
+DROP COLUMN IF EXISTS modified_by,
+DROP COLUMN IF EXISTS operation;
+
+ALTER TABLE tender
+DROP COLUMN IF EXISTS modified_by,
+DROP COLUMN IF EXISTS operation;
+
+DROP TYPE IF EXISTS version_operation;
@@ -0,0 +1,67 @@
+DO $$
+BEGIN
+    CREATE TYPE version_operation AS ENUM (
+        'Unknown',
+        'Create',
+        'Edit',
+        'StatusChange',
+        'Rollback',
+        'Decision',
+        'Close'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+ALTER TABLE tender
+ADD COLUMN IF NOT EXISTS modified_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS operation version_operation NOT NULL DEFAULT 'Unknown';
+
+ALTER TABLE tender
+ALTER COLUMN operation DROP DEFAULT;
+
+
+ALTER TABLE tender_archive
+ADD COLUMN IF NOT EXISTS modified_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS operation version_operation NOT NULL DEFAULT 'Unknown';
+
+
+ALTER TABLE bid
+ADD COLUMN IF NOT EXISTS modified_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS operation version_operation NOT NULL DEFAULT 'Unknown';
+
+ALTER TABLE bid
+ALTER COLUMN operation DROP DEFAULT;
+
+
+ALTER TABLE bid_archive
+ADD COLUMN IF NOT EXISTS modified_by UUID REFERENCES employee(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS operation version_operation NOT NULL DEFAULT 'Unknown';
+
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason, budget_min, budget_max, currency, budget_policy,
+        modified_by, operation)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason, new.budget_min, new.budget_max, new.currency, new.budget_policy,
+        new.modified_by, new.operation);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+
+CREATE OR REPLACE FUNCTION archive_bid()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO bid_archive (id, name, description, status, tender_id, author_type, author_id, version, created_at, updated_at,
+        price, currency, delivery_days, valid_until, out_of_budget, modified_by, operation)
+    VALUES (new.id, new.name, new.description, new.status, new.tender_id, new.author_type, new.author_id, new.version, new.created_at, new.updated_at,
+        new.price, new.currency, new.delivery_days, new.valid_until, new.out_of_budget, new.modified_by, new.operation);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
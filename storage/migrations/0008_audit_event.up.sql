@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS audit_event (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID,
+    organization_id UUID NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    before_version INTEGER,
+    after_version INTEGER,
+    request_id TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+
+CREATE INDEX IF NOT EXISTS audit_event_organization_idx
+ON audit_event (organization_id, created_at, id);
+
+CREATE INDEX IF NOT EXISTS audit_event_entity_idx
+ON audit_event (entity_type, entity_id);
+
+
+CREATE OR REPLACE FUNCTION forbid_audit_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+
+CREATE OR REPLACE TRIGGER trg_forbid_audit_change
+BEFORE UPDATE OR DELETE ON audit_event
+FOR EACH ROW
+EXECUTE PROCEDURE forbid_audit_change();
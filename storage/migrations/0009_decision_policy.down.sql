@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS bid_approved_decision (
+    bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(bid_id, user_id)
+);
+
+INSERT INTO bid_approved_decision(bid_id, user_id, created_at)
+SELECT bid_id, user_id, created_at
+FROM bid_decision
+WHERE decision = 'Approved'
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS bid_decision;
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason, budget_min, budget_max, currency, budget_policy,
+        modified_by, operation)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason, new.budget_min, new.budget_max, new.currency, new.budget_policy,
+        new.modified_by, new.operation);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE tender_archive
+DROP COLUMN IF EXISTS approval_rule,
+DROP COLUMN IF EXISTS approval_threshold,
+DROP COLUMN IF EXISTS rejection_rule;
+
+ALTER TABLE tender
+DROP COLUMN IF EXISTS approval_rule,
+DROP COLUMN IF EXISTS approval_threshold,
+DROP COLUMN IF EXISTS rejection_rule;
+
+DROP TYPE IF EXISTS rejection_rule;
+
+DROP TYPE IF EXISTS approval_rule;
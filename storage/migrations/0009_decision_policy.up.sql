@@ -0,0 +1,67 @@
+DO $$
+BEGIN
+    CREATE TYPE approval_rule AS ENUM (
+        'Quorum',
+        'Fixed',
+        'Percent',
+        'Unanimous',
+        'FirstApprover'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+DO $$
+BEGIN
+    CREATE TYPE rejection_rule AS ENUM (
+        'FirstVeto',
+        'Majority'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+ALTER TABLE tender
+ADD COLUMN IF NOT EXISTS approval_rule approval_rule NOT NULL DEFAULT 'Quorum',
+ADD COLUMN IF NOT EXISTS approval_threshold INTEGER,
+ADD COLUMN IF NOT EXISTS rejection_rule rejection_rule NOT NULL DEFAULT 'FirstVeto';
+
+
+ALTER TABLE tender_archive
+ADD COLUMN IF NOT EXISTS approval_rule approval_rule NOT NULL DEFAULT 'Quorum',
+ADD COLUMN IF NOT EXISTS approval_threshold INTEGER,
+ADD COLUMN IF NOT EXISTS rejection_rule rejection_rule NOT NULL DEFAULT 'FirstVeto';
+
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason, budget_min, budget_max, currency, budget_policy,
+        modified_by, operation, approval_rule, approval_threshold, rejection_rule)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason, new.budget_min, new.budget_max, new.currency, new.budget_policy,
+        new.modified_by, new.operation, new.approval_rule, new.approval_threshold, new.rejection_rule);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+
+CREATE TABLE IF NOT EXISTS bid_decision (
+    bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+    decision bid_status NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(bid_id, user_id)
+);
+
+INSERT INTO bid_decision(bid_id, user_id, decision, created_at)
+SELECT bid_id, user_id, 'Approved', COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM bid_approved_decision
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS bid_approved_decision;
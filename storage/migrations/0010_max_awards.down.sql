@@ -0,0 +1,18 @@
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason, budget_min, budget_max, currency, budget_policy,
+        modified_by, operation, approval_rule, approval_threshold, rejection_rule)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason, new.budget_min, new.budget_max, new.currency, new.budget_policy,
+        new.modified_by, new.operation, new.approval_rule, new.approval_threshold, new.rejection_rule);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE tender_archive
+DROP COLUMN IF EXISTS max_awards;
+
+ALTER TABLE tender
+DROP COLUMN IF EXISTS max_awards;
@@ -0,0 +1,20 @@
+ALTER TABLE tender
+ADD COLUMN IF NOT EXISTS max_awards INTEGER CHECK (max_awards > 0);
+
+
+ALTER TABLE tender_archive
+ADD COLUMN IF NOT EXISTS max_awards INTEGER;
+
+
+CREATE OR REPLACE FUNCTION archive_tender()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tender_archive (id, name, description, type, status, organization_id, version, created_at, updated_at,
+        submission_deadline, decision_deadline, close_reason, budget_min, budget_max, currency, budget_policy,
+        modified_by, operation, approval_rule, approval_threshold, rejection_rule, max_awards)
+    VALUES (new.id, new.name, new.description, new.type, new.status, new.organization_id, new.version, new.created_at, new.updated_at,
+        new.submission_deadline, new.decision_deadline, new.close_reason, new.budget_min, new.budget_max, new.currency, new.budget_policy,
+        new.modified_by, new.operation, new.approval_rule, new.approval_threshold, new.rejection_rule, new.max_awards);
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
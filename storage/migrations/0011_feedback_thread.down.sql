@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS bid_feedback_thread_idx;
+
+DELETE FROM bid_feedback
+WHERE side = 'Author';
+
+ALTER TABLE bid_feedback
+DROP COLUMN IF EXISTS parent_id,
+DROP COLUMN IF EXISTS side,
+DROP COLUMN IF EXISTS visibility,
+DROP COLUMN IF EXISTS updated_at,
+DROP COLUMN IF EXISTS deleted_at;
+
+DROP TYPE IF EXISTS feedback_visibility;
+
+DROP TYPE IF EXISTS feedback_side;
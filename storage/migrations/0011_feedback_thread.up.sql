@@ -0,0 +1,37 @@
+DO $$
+BEGIN
+    CREATE TYPE feedback_side AS ENUM (
+        'Reviewer',
+        'Author',
+        'System'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+DO $$
+BEGIN
+    CREATE TYPE feedback_visibility AS ENUM (
+        'Public',
+        'Internal'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+ALTER TABLE bid_feedback
+ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES bid_feedback(id) ON DELETE CASCADE,
+ADD COLUMN IF NOT EXISTS side feedback_side NOT NULL DEFAULT 'Reviewer',
+ADD COLUMN IF NOT EXISTS visibility feedback_visibility NOT NULL DEFAULT 'Public',
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+UPDATE bid_feedback
+SET side = 'System'
+WHERE user_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS bid_feedback_thread_idx ON bid_feedback(bid_id, created_at, id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS bid_rating (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID NOT NULL UNIQUE REFERENCES bid(id) ON DELETE CASCADE,
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    author_organization_id UUID REFERENCES organization(id) ON DELETE SET NULL,
+    user_id UUID REFERENCES employee(id) ON DELETE SET NULL,
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    quality SMALLINT NOT NULL CHECK (quality BETWEEN 1 AND 5),
+    timeliness SMALLINT NOT NULL CHECK (timeliness BETWEEN 1 AND 5),
+    price SMALLINT NOT NULL CHECK (price BETWEEN 1 AND 5),
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS bid_rating_author_idx ON bid_rating(author_id);
+CREATE INDEX IF NOT EXISTS bid_rating_author_organization_idx ON bid_rating(author_organization_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notification_subscription;
+DROP TABLE IF EXISTS notification;
+DROP TYPE IF EXISTS notification_channel;
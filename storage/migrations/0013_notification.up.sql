@@ -0,0 +1,40 @@
+DO $$
+BEGIN
+    CREATE TYPE notification_channel AS ENUM (
+        'Inbox',
+        'Email',
+        'Webhook'
+    );
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END
+$$;
+
+
+CREATE TABLE IF NOT EXISTS notification (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    actor_id UUID,
+    organization_id UUID NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP,
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS notification_inbox_idx ON notification(user_id, created_at, id);
+
+
+CREATE TABLE IF NOT EXISTS notification_subscription (
+    user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+    channel notification_channel NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    target TEXT,
+    actions TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, channel)
+);
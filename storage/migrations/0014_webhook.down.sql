@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS webhook_dead_letter;
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook_endpoint;
+DROP TYPE IF EXISTS webhook_delivery_status;
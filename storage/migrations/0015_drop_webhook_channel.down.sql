@@ -0,0 +1 @@
+ALTER TYPE notification_channel ADD VALUE IF NOT EXISTS 'Webhook';
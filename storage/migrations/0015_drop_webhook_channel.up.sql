@@ -0,0 +1,13 @@
+DELETE FROM notification_subscription WHERE channel = 'Webhook';
+
+ALTER TYPE notification_channel RENAME TO notification_channel_old;
+
+CREATE TYPE notification_channel AS ENUM (
+    'Inbox',
+    'Email'
+);
+
+ALTER TABLE notification_subscription
+ALTER COLUMN channel TYPE notification_channel USING channel::text::notification_channel;
+
+DROP TYPE notification_channel_old;
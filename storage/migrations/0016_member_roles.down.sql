@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION grant_default_role()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO organization_role (organization_id, user_id, role)
+    VALUES (new.organization_id, new.user_id,
+        CASE WHEN EXISTS (
+            SELECT 1
+            FROM organization_role
+            WHERE organization_id = new.organization_id
+            AND role = 'Owner')
+        THEN 'Viewer'::organization_role
+        ELSE 'Owner'::organization_role END)
+    ON CONFLICT DO NOTHING;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
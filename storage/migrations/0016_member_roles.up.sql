@@ -0,0 +1,24 @@
+-- The first responsible of an organization becomes its Owner. Later responsibles
+-- get Editor and Approver, which together keep the rights every responsible had
+-- before roles: create, edit and publish tenders, edit bids, decide and review.
+CREATE OR REPLACE FUNCTION grant_default_role()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM organization_role
+        WHERE organization_id = new.organization_id
+        AND role = 'Owner')
+    THEN
+        INSERT INTO organization_role (organization_id, user_id, role)
+        VALUES (new.organization_id, new.user_id, 'Editor'),
+               (new.organization_id, new.user_id, 'Approver')
+        ON CONFLICT DO NOTHING;
+    ELSE
+        INSERT INTO organization_role (organization_id, user_id, role)
+        VALUES (new.organization_id, new.user_id, 'Owner')
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
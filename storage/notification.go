@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) NotificationRecipients(ctx context.Context, e model.AuditEvent) ([]uuid.UUID, error) {
+
+	q := query{}
+	var recipients string
+
+	switch e.EntityType {
+	case model.EntityTender:
+		recipients = fmt.Sprintf(`	SELECT user_id FROM organization_responsible WHERE organization_id = %s
+									UNION
+									SELECT author_id FROM bid WHERE tender_id = %s AND status <> 'Created'`,
+			q.arg(e.OrganizationId), q.arg(e.EntityId))
+	case model.EntityBid:
+		bidId := q.arg(e.EntityId)
+		recipients = `SELECT NULL::uuid`
+		if e.VisibleTo(model.FeedbackSideAuthor) {
+			recipients += fmt.Sprintf(`
+									UNION
+									SELECT author_id FROM bid WHERE id = %[1]s
+									UNION
+									SELECT r.user_id
+									FROM organization_responsible r
+									JOIN organization_responsible a ON a.organization_id = r.organization_id
+									JOIN bid b ON b.author_id = a.user_id
+									WHERE b.id = %[1]s AND b.author_type = 'Organization'`, bidId)
+		}
+		if e.VisibleTo(model.FeedbackSideReviewer) {
+			recipients += fmt.Sprintf(`
+									UNION
+									SELECT r.user_id
+									FROM organization_responsible r
+									JOIN tender t ON t.organization_id = r.organization_id
+									JOIN bid b ON b.tender_id = t.id
+									WHERE b.id = %[1]s AND b.status <> 'Created'`, bidId)
+		}
+	default:
+		recipients = `SELECT id FROM employee WHERE ` + q.set("id", e.EntityId)
+	}
+
+	query := fmt.Sprintf(`	SELECT DISTINCT r.*
+							FROM (%s) r(user_id)
+							WHERE user_id IS NOT NULL
+							AND user_id IS DISTINCT FROM %s;`, recipients, q.arg(e.ActorId))
+
+	row, err := s.conn.Query(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(row, pgx.RowTo[uuid.UUID])
+
+}
+
+func (s *Storage) UserSubscriptions(ctx context.Context, userId uuid.UUID) ([]model.Subscription, error) {
+
+	query := `	SELECT user_id, channel, enabled, target, actions, updated_at
+				FROM notification_subscription
+				WHERE user_id = $1;`
+
+	row, err := s.conn.Query(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(row, scanSubscription)
+
+}
+
+func scanSubscription(row pgx.CollectableRow) (model.Subscription, error) {
+
+	sub := model.Subscription{}
+	actions := []string{}
+	if err := row.Scan(&sub.UserId, &sub.Channel, &sub.Enabled, &sub.Target, &actions, &sub.UpdatedAt); err != nil {
+		return model.Subscription{}, err
+	}
+
+	sub.Actions = make([]model.AuditAction, 0, len(actions))
+	for _, a := range actions {
+		sub.Actions = append(sub.Actions, model.AuditAction(a))
+	}
+
+	return sub, nil
+
+}
+
+func (s *Storage) AddNotification(ctx context.Context, n model.Notification) error {
+
+	insert := `	INSERT INTO notification(id, user_id, event_id, actor_id, organization_id, entity_type, entity_id, action, message, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				ON CONFLICT (user_id, event_id) DO NOTHING;`
+
+	_, err := s.conn.Exec(ctx, insert, n.Id, n.UserId, n.EventId, n.ActorId, n.OrganizationId, n.EntityType, n.EntityId,
+		n.Action, n.Message, n.CreatedAt)
+
+	return err
+
+}
+
+func (s *Storage) ReadNotifications(ctx context.Context, username string, filter model.NotificationFilter, opts model.ListOptions) (model.Page[model.Notification], error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return model.Page[model.Notification]{}, err
+	}
+
+	q := query{}
+	from := `FROM notification WHERE ` + q.set("user_id", userId)
+	if filter.Unread {
+		from += ` AND read_at IS NULL`
+	}
+
+	return readPage[model.Notification](ctx, s, "notification", from, q, opts)
+
+}
+
+func (s *Storage) MarkNotificationRead(ctx context.Context, username string, notificationId uuid.UUID) (model.Notification, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	update := `	UPDATE notification
+				SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+				WHERE id = $1 AND user_id = $2
+				RETURNING *;`
+
+	row, err := s.conn.Query(ctx, update, notificationId, userId)
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	n, err := pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Notification])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Notification{}, ErrNotificationNotFound
+	}
+
+	return n, err
+
+}
+
+func (s *Storage) MarkNotificationsRead(ctx context.Context, username string) (int, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := s.conn.Exec(ctx, `UPDATE notification SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL;`, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+
+}
+
+func (s *Storage) ReadSubscriptions(ctx context.Context, username string) ([]model.Subscription, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.UserSubscriptions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.EffectiveSubscriptions(userId, stored), nil
+
+}
+
+func (s *Storage) UpdateSubscription(ctx context.Context, username string, sub model.Subscription) (model.Subscription, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+
+	actions := make([]string, 0, len(sub.Actions))
+	for _, a := range sub.Actions {
+		actions = append(actions, string(a))
+	}
+
+	upsert := `	INSERT INTO notification_subscription(user_id, channel, enabled, target, actions)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (user_id, channel) DO UPDATE
+				SET enabled = excluded.enabled,
+					target = excluded.target,
+					actions = excluded.actions,
+					updated_at = CURRENT_TIMESTAMP
+				RETURNING user_id, channel, enabled, target, actions, updated_at;`
+
+	row, err := s.conn.Query(ctx, upsert, userId, sub.Channel, sub.Enabled, sub.Target, actions)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+
+	return pgx.CollectOneRow(row, scanSubscription)
+
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"zadanie/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type query struct {
+	args []any
+}
+
+func (q *query) arg(value any) string {
+
+	q.args = append(q.args, value)
+
+	return "$" + strconv.Itoa(len(q.args))
+
+}
+
+func (q *query) set(column string, value any) string {
+	return column + " = " + q.arg(value)
+}
+
+func in[T any](q *query, column string, values []T) string {
+
+	placeholders := make([]string, 0, len(values))
+	for _, v := range values {
+		placeholders = append(placeholders, q.arg(v))
+	}
+
+	return column + " IN (" + strings.Join(placeholders, ", ") + ")"
+
+}
+
+func (q *query) keyset(table string, opts model.ListOptions) (after, order string) {
+
+	column, id := table+"."+opts.Sort.Field(), table+".id"
+
+	dir, op := "ASC", ">"
+	if opts.Sort.Desc() {
+		dir, op = "DESC", "<"
+	}
+
+	after = "TRUE"
+	if c := opts.After; c != nil {
+		if c.Key == nil {
+			after = fmt.Sprintf("(%s IS NULL AND %s > %s)", column, id, q.arg(c.Id))
+		} else {
+			key := q.arg(c.Key)
+			after = fmt.Sprintf("(%[1]s %[2]s %[3]s OR (%[1]s = %[3]s AND %[4]s > %[5]s) OR %[1]s IS NULL)", column, op, key, id, q.arg(c.Id))
+		}
+	}
+
+	order = fmt.Sprintf("ORDER BY %s %s NULLS LAST, %s ASC LIMIT %s", column, dir, id, q.arg(opts.Limit+1))
+	if opts.After == nil {
+		order += " OFFSET " + q.arg(opts.Offset)
+	}
+
+	return after, order
+
+}
+
+func readPage[T model.Listable](ctx context.Context, s *Storage, table, from string, q query, opts model.ListOptions) (model.Page[T], error) {
+
+	filter := slices.Clone(q.args)
+	after, order := q.keyset(table, opts)
+
+	row, err := s.conn.Query(ctx, "SELECT "+table+".* "+from+" AND "+after+" "+order+";", q.args...)
+	if err != nil {
+		return model.Page[T]{}, err
+	}
+
+	items, err := pgx.CollectRows(row, pgx.RowToStructByNameLax[T])
+	if err != nil {
+		return model.Page[T]{}, err
+	}
+
+	page := model.NewPage(items, opts)
+
+	if opts.Total {
+		total := 0
+		if err := s.conn.QueryRow(ctx, "SELECT count(*) "+from+";", filter...).Scan(&total); err != nil {
+			return model.Page[T]{}, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+
+}
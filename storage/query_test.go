@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+var hostile = []string{
+	`'; DROP TABLE tender; --`,
+	`" OR 1=1 --`,
+	`x' OR 'a'='a`,
+	`$1`,
+	`%_\`,
+	`name = name, status = 'Closed'`,
+	"\x00\n\t",
+	`Доставка «срочно» 🚚`,
+	"\u200b\ufeff\u202e",
+}
+
+func TestQueryArg(t *testing.T) {
+
+	for _, value := range hostile {
+		t.Run(value, func(t *testing.T) {
+
+			q := query{}
+			first := q.arg(42)
+			second := q.arg(value)
+
+			if first != "$1" || second != "$2" {
+				t.Fatalf("placeholders %q, %q", first, second)
+			}
+			if !reflect.DeepEqual(q.args, []any{42, value}) {
+				t.Fatalf("args %#v", q.args)
+			}
+
+		})
+	}
+
+}
+
+func TestQuerySet(t *testing.T) {
+
+	for _, value := range hostile {
+		t.Run(value, func(t *testing.T) {
+
+			q := query{}
+			parts := []string{
+				q.set("name", value),
+				q.set("description", value),
+				q.set("type", model.TenderServiceType(value)),
+			}
+			sql := "UPDATE tender SET " + strings.Join(parts, ", ") + " WHERE id = " + q.arg(uuid.Nil) + " RETURNING *;"
+
+			want := "UPDATE tender SET name = $1, description = $2, type = $3 WHERE id = $4 RETURNING *;"
+			if sql != want {
+				t.Fatalf("sql %q, want %q", sql, want)
+			}
+			if !reflect.DeepEqual(q.args, []any{value, value, model.TenderServiceType(value), uuid.Nil}) {
+				t.Fatalf("args %#v", q.args)
+			}
+
+		})
+	}
+
+}
+
+func TestQueryIn(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		values []model.TenderServiceType
+		sql    string
+	}{
+		{"one", []model.TenderServiceType{"Delivery"}, "type IN ($2)"},
+		{"hostile", []model.TenderServiceType{`Delivery') OR ('1'='1`, `'; --`, `Стройка`}, "type IN ($2, $3, $4)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			q := query{}
+			q.arg("search")
+			sql := in(&q, "type", tt.values)
+
+			if sql != tt.sql {
+				t.Fatalf("sql %q, want %q", sql, tt.sql)
+			}
+
+			want := []any{"search"}
+			for _, v := range tt.values {
+				want = append(want, v)
+			}
+			if !reflect.DeepEqual(q.args, want) {
+				t.Fatalf("args %#v, want %#v", q.args, want)
+			}
+
+		})
+	}
+
+}
+
+func TestQueryKeyset(t *testing.T) {
+
+	id := uuid.Must(uuid.FromString("550e8400-e29b-41d4-a716-446655440000"))
+
+	tests := []struct {
+		name  string
+		opts  model.ListOptions
+		after string
+		order string
+		args  []any
+	}{
+		{
+			name:  "first page",
+			opts:  model.ListOptions{Sort: "name", Limit: 5, Offset: 10},
+			after: "TRUE",
+			order: "ORDER BY tender.name ASC NULLS LAST, tender.id ASC LIMIT $2 OFFSET $3",
+			args:  []any{"filter", 6, 10},
+		},
+		{
+			name:  "hostile key",
+			opts:  model.ListOptions{Sort: "-name", Limit: 5, After: &model.Cursor{Sort: "-name", Key: `x' OR 1=1 --`, Id: id}},
+			after: "(tender.name < $2 OR (tender.name = $2 AND tender.id > $3) OR tender.name IS NULL)",
+			order: "ORDER BY tender.name DESC NULLS LAST, tender.id ASC LIMIT $4",
+			args:  []any{"filter", `x' OR 1=1 --`, id, 6},
+		},
+		{
+			name:  "null key",
+			opts:  model.ListOptions{Sort: "price", Limit: 1, After: &model.Cursor{Sort: "price", Id: id}},
+			after: "(tender.price IS NULL AND tender.id > $2)",
+			order: "ORDER BY tender.price ASC NULLS LAST, tender.id ASC LIMIT $3",
+			args:  []any{"filter", id, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			q := query{}
+			q.arg("filter")
+			after, order := q.keyset("tender", tt.opts)
+
+			if after != tt.after {
+				t.Fatalf("after %q, want %q", after, tt.after)
+			}
+			if order != tt.order {
+				t.Fatalf("order %q, want %q", order, tt.order)
+			}
+			if !reflect.DeepEqual(q.args, tt.args) {
+				t.Fatalf("args %#v, want %#v", q.args, tt.args)
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) RateBid(ctx context.Context, bidId uuid.UUID, username string, rating model.BidRating) (model.BidRating, error) {
+	return atomic(ctx, s, func(s *Storage) (model.BidRating, error) {
+
+		userId, err := s.userId(ctx, username)
+		if err != nil {
+			return model.BidRating{}, err
+		}
+
+		bid, err := s.lockBid(ctx, bidId)
+		if err != nil {
+			return model.BidRating{}, err
+		}
+
+		tender, err := s.tender(ctx, bid.TenderId)
+		if err != nil {
+			return model.BidRating{}, err
+		}
+
+		if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionFeedback) {
+			return model.BidRating{}, ErrNotEnoughPerm
+		}
+
+		if bid.Status != model.BidStatusApproved || tender.Status != model.TenderStatusClosed {
+			return model.BidRating{}, ErrBidNotRatable
+		}
+
+		var authorOrgId *uuid.UUID
+		if orgId, err := s.userOrgId(ctx, bid.AuthorId); err == nil {
+			authorOrgId = &orgId
+		}
+
+		insert := `	INSERT INTO bid_rating(bid_id, tender_id, author_id, author_organization_id, user_id, organization_id,
+						quality, timeliness, price, comment)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+					ON CONFLICT (bid_id) DO NOTHING
+					RETURNING *;`
+
+		row, err := s.conn.Query(ctx, insert, bidId, tender.Id, bid.AuthorId, authorOrgId, userId, tender.OrganizationId,
+			rating.Quality, rating.Timeliness, rating.Price, rating.Comment)
+		if err != nil {
+			return model.BidRating{}, err
+		}
+
+		rating, err = pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.BidRating])
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.BidRating{}, ErrBidRated
+		}
+		if err != nil {
+			return model.BidRating{}, err
+		}
+
+		return rating, audit(ctx, s.conn, model.BidEvent(model.AuditBidRate, &userId, tender.OrganizationId, &bid, bid))
+
+	})
+}
+
+func (s *Storage) ratingFilter(ctx context.Context, username string, filter model.ReputationFilter) (string, query, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return "", query{}, err
+	}
+
+	orgId, err := s.userOrgId(ctx, userId)
+	if err != nil {
+		return "", query{}, err
+	}
+
+	if !s.checkPermission(ctx, userId, orgId, model.ActionView) {
+		return "", query{}, ErrNotEnoughPerm
+	}
+
+	q := query{}
+	if filter.OrganizationId != nil {
+		return `FROM bid_rating WHERE ` + q.set("author_organization_id", *filter.OrganizationId), q, nil
+	}
+
+	authorId, err := s.userId(ctx, filter.Author)
+	if err != nil {
+		return "", query{}, err
+	}
+
+	return `FROM bid_rating WHERE ` + q.set("author_id", authorId), q, nil
+
+}
+
+func (s *Storage) ReadReputation(ctx context.Context, username string, filter model.ReputationFilter) (model.Reputation, error) {
+
+	from, q, err := s.ratingFilter(ctx, username, filter)
+	if err != nil {
+		return model.Reputation{}, err
+	}
+
+	query := `	SELECT count(*) AS ratings,
+					avg(quality)::float8 AS quality,
+					avg(timeliness)::float8 AS timeliness,
+					avg(price)::float8 AS price,
+					avg((quality + timeliness + price) / 3.0)::float8 AS overall ` + from + `;`
+
+	row, err := s.conn.Query(ctx, query, q.args...)
+	if err != nil {
+		return model.Reputation{}, err
+	}
+
+	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Reputation])
+
+}
+
+func (s *Storage) ReadRatings(ctx context.Context, username string, filter model.ReputationFilter, opts model.ListOptions) (model.Page[model.BidRating], error) {
+
+	from, q, err := s.ratingFilter(ctx, username, filter)
+	if err != nil {
+		return model.Page[model.BidRating]{}, err
+	}
+
+	return readPage[model.BidRating](ctx, s, "bid_rating", from, q, opts)
+
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) ReadRoles(ctx context.Context, orgId uuid.UUID, username string) ([]model.RoleAssignment, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.checkPermission(ctx, userId, orgId, model.ActionView) {
+		return nil, ErrNotEnoughPerm
+	}
+
+	query := `	SELECT r.organization_id, r.user_id, e.username, r.role, r.created_at
+				FROM organization_role r
+				JOIN employee e ON e.id = r.user_id
+				WHERE r.organization_id = $1
+				ORDER BY e.username ASC, r.role ASC;`
+
+	row, err := s.conn.Query(ctx, query, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.RoleAssignment])
+
+}
+
+func (s *Storage) GrantRole(ctx context.Context, orgId uuid.UUID, username string, target string, role model.Role) (model.RoleAssignment, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return model.RoleAssignment{}, err
+	}
+
+	if !s.checkPermission(ctx, userId, orgId, model.ActionManageRoles) {
+		return model.RoleAssignment{}, ErrNotEnoughPerm
+	}
+
+	targetId, err := s.userId(ctx, target)
+	if err != nil {
+		return model.RoleAssignment{}, err
+	}
+
+	if !s.checkRelationToOrganization(ctx, targetId, orgId) {
+		return model.RoleAssignment{}, ErrNotMember
+	}
+
+	insert := `	INSERT INTO organization_role(organization_id, user_id, role)
+				VALUES ($1, $2, $3)
+				ON CONFLICT DO NOTHING;`
+
+	query := `	SELECT organization_id, user_id, $4::text AS username, role, created_at
+				FROM organization_role
+				WHERE organization_id = $1 AND user_id = $2 AND role = $3;`
+
+	rows := []model.RoleAssignment{}
+	err = s.tx(ctx, func(tx pgx.Tx) error {
+
+		tag, err := tx.Exec(ctx, insert, orgId, targetId, role)
+		if err != nil {
+			return err
+		}
+
+		row, err := tx.Query(ctx, query, orgId, targetId, role, target)
+		if err != nil {
+			return err
+		}
+
+		rows, err = pgx.CollectRows(row, pgx.RowToStructByNameLax[model.RoleAssignment])
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+
+		return audit(ctx, tx, model.RoleEvent(model.AuditRoleGrant, userId, orgId, targetId))
+
+	})
+	if err != nil {
+		return model.RoleAssignment{}, err
+	}
+
+	if len(rows) == 0 {
+		return model.RoleAssignment{}, ErrRoleNotFound
+	}
+
+	return rows[0], nil
+
+}
+
+func (s *Storage) RevokeRole(ctx context.Context, orgId uuid.UUID, username string, target string, role model.Role) error {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if !s.checkPermission(ctx, userId, orgId, model.ActionManageRoles) {
+		return ErrNotEnoughPerm
+	}
+
+	targetId, err := s.userId(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	owners := `	SELECT user_id
+				FROM organization_role
+				WHERE organization_id = $1
+				AND role = 'Owner'
+				ORDER BY user_id
+				FOR UPDATE;`
+
+	delete := `	DELETE FROM organization_role
+				WHERE organization_id = $1
+				AND user_id = $2
+				AND role = $3;`
+
+	return s.tx(ctx, func(tx pgx.Tx) error {
+
+		row, err := tx.Query(ctx, owners, orgId)
+		if err != nil {
+			return err
+		}
+
+		ownerIds, err := pgx.CollectRows(row, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return err
+		}
+
+		if role == model.RoleOwner && slices.Contains(ownerIds, targetId) && len(ownerIds) <= 1 {
+			return ErrLastOwner
+		}
+
+		tag, err := tx.Exec(ctx, delete, orgId, targetId, role)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return ErrRoleNotFound
+		}
+
+		return audit(ctx, tx, model.RoleEvent(model.AuditRoleRevoke, userId, orgId, targetId))
+
+	})
+
+}
@@ -0,0 +1,42 @@
+package storage_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestDefaultRoles(t *testing.T) {
+
+	s, pool := newTestStorage(t)
+
+	prefix := uuid.Must(uuid.NewV4()).String()[:8]
+	owner, member := prefix+"-owner", prefix+"-member"
+	org := addOrganization(t, pool, prefix+" org", addEmployee(t, pool, owner), addEmployee(t, pool, member))
+
+	assignments, err := s.ReadRoles(context.Background(), org, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string][]model.Role{}
+	for _, a := range assignments {
+		got[a.Username] = append(got[a.Username], a.Role)
+	}
+	for _, r := range got {
+		slices.Sort(r)
+	}
+
+	want := map[string][]model.Role{
+		owner:  {model.RoleOwner},
+		member: {model.RoleApprover, model.RoleEditor},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("roles %v, want %v", got, want)
+	}
+
+}
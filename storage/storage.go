@@ -2,53 +2,95 @@ package storage
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"strconv"
+	"zadanie/config"
+	"zadanie/model"
+	"zadanie/notify"
 
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+type conn interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type Storage struct {
-	conn *pgxpool.Pool
+	pool *pgxpool.Pool
+	conn conn
+	bus  *notify.Bus
+	hub  *notify.Hub
 }
 
-func NewStorage(ctx context.Context) (*Storage, error) {
+func connect(ctx context.Context, cfg config.Postgres) (*pgxpool.Pool, error) {
 
-	port, err := strconv.Atoi(os.Getenv("POSTGRES_PORT"))
+	poolCfg, err := pgxpool.ParseConfig(cfg.ConnString)
 	if err != nil {
 		return nil, err
 	}
 
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
-		os.Getenv("POSTGRES_HOST"), port, os.Getenv("POSTGRES_USERNAME"), os.Getenv("POSTGRES_PASSWORD"), os.Getenv("POSTGRES_DATABASE"))
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MinConns = cfg.MinConns
 
-	conn, err := pgxpool.New(ctx, psqlInfo)
+	conn, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := conn.Ping(ctx); err != nil {
+		conn.Close()
 		return nil, err
 	}
 
-	if err := create(ctx, conn); err != nil {
+	return conn, nil
+
+}
+
+func NewStorage(ctx context.Context, cfg config.Postgres) (*Storage, error) {
+
+	conn, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := (&Migrator{conn: conn}).Up(ctx); err != nil {
+		conn.Close()
 		return nil, err
 	}
 
 	return &Storage{
+		pool: conn,
 		conn: conn,
+		hub:  notify.NewHub(),
 	}, nil
 
 }
 
+func (s *Storage) SetBus(bus *notify.Bus) {
+	s.bus = bus
+}
+
 func (s *Storage) Ping(ctx context.Context) error {
-	return s.conn.Ping(ctx)
+	return s.pool.Ping(ctx)
 }
 
 func (s *Storage) Close() {
-	s.conn.Close()
+	s.pool.Close()
+}
+
+func (s *Storage) Employee(ctx context.Context, username string) (model.Employee, error) {
+
+	id, err := s.userId(ctx, username)
+	if err != nil {
+		return model.Employee{}, err
+	}
+
+	return model.Employee{Id: id, Username: username}, nil
+
 }
 
 func (s *Storage) userId(ctx context.Context, username string) (uuid.UUID, error) {
@@ -68,7 +110,7 @@ func (s *Storage) userOrgId(ctx context.Context, userId uuid.UUID) (uuid.UUID, e
 	id := uuid.UUID{}
 	query := `SELECT organization_id FROM organization_responsible WHERE user_id = $1;`
 	if err := s.conn.QueryRow(ctx, query, userId).Scan(&id); err != nil {
-		return uuid.UUID{}, err
+		return uuid.UUID{}, ErrOrganizationNotFound
 	}
 
 	return id, nil
@@ -96,14 +138,51 @@ func (s *Storage) checkRelationToOrganization(ctx context.Context, userId, orgId
 
 }
 
-func (s *Storage) quorum(ctx context.Context, orgId uuid.UUID) (int, error) {
+func (s *Storage) checkPermission(ctx context.Context, userId, orgId uuid.UUID, action model.Action) bool {
 
 	res := 0
-	query := `SELECT COUNT(*) FROM organization_responsible WHERE organization_id = $1;`
-	if err := s.conn.QueryRow(ctx, query, orgId).Scan(&res); err != nil {
-		return 0, err
+	query := `	SELECT 1
+				FROM organization_role r
+				JOIN organization_responsible o
+					ON o.organization_id = r.organization_id
+					AND o.user_id = r.user_id
+				WHERE r.user_id = $1
+				AND r.organization_id = $2
+				AND r.role::text = ANY($3)
+				LIMIT 1;`
+	err := s.conn.QueryRow(ctx, query, userId, orgId, roleNames(model.RolesAllowedTo(action))).Scan(&res)
+	return err == nil
+
+}
+
+func (s *Storage) voters(ctx context.Context, orgId uuid.UUID) ([]model.Employee, error) {
+
+	query := `	SELECT DISTINCT e.id, e.username
+				FROM organization_role r
+				JOIN organization_responsible o
+					ON o.organization_id = r.organization_id
+					AND o.user_id = r.user_id
+				JOIN employee e ON e.id = r.user_id
+				WHERE r.organization_id = $1
+				AND r.role::text = ANY($2)
+				ORDER BY e.username ASC;`
+
+	row, err := s.conn.Query(ctx, query, orgId, roleNames(model.RolesAllowedTo(model.ActionDecideBid)))
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Employee])
+
+}
+
+func roleNames(roles []model.Role) []string {
+
+	res := make([]string, 0, len(roles))
+	for _, r := range roles {
+		res = append(res, string(r))
 	}
 
-	return min(3, res), nil
+	return res
 
 }
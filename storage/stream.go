@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+const changesChannel = "tender_changes"
+
+func notifyChange(ctx context.Context, db conn, e model.AuditEvent) error {
+
+	c, ok := e.TenderChange()
+	if !ok {
+		return nil
+	}
+
+	if c.EntityType == model.EntityBid {
+
+		query := `	SELECT t.organization_id, b.status, (
+						SELECT organization_id
+						FROM organization_responsible
+						WHERE user_id = b.author_id
+						LIMIT 1)
+					FROM bid b
+					JOIN tender t ON t.id = b.tender_id
+					WHERE b.id = $1;`
+
+		var status model.BidStatus
+		if err := db.QueryRow(ctx, query, e.EntityId).Scan(&c.TenderOrganizationId, &status,
+			&c.BidOrganizationId); err != nil {
+			return err
+		}
+		if len(c.BidStatus) == 0 {
+			c.BidStatus = status
+		}
+	}
+
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, `SELECT pg_notify($1, $2);`, changesChannel, string(payload))
+	return err
+
+}
+
+func (s *Storage) ListenChanges(ctx context.Context) {
+
+	for {
+
+		if err := s.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("stream: listen %s: %s", changesChannel, err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+
+	}
+
+}
+
+func (s *Storage) listen(ctx context.Context) error {
+
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `LISTEN `+changesChannel+`;`); err != nil {
+		return err
+	}
+
+	for {
+
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			conn.Conn().Close(context.Background())
+			return err
+		}
+
+		c := model.TenderChange{}
+		if err := json.Unmarshal([]byte(n.Payload), &c); err != nil {
+			log.Printf("stream: malformed notification: %s", err.Error())
+			continue
+		}
+
+		s.hub.Publish(c)
+
+	}
+
+}
+
+func (s *Storage) WatchTender(ctx context.Context, tenderId uuid.UUID, username string) (<-chan model.TenderChange, error) {
+
+	tender, err := s.tender(ctx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	orgId, err := s.userOrgId(ctx, userId)
+	if err != nil {
+		orgId = uuid.Nil
+	}
+
+	if tender.Status != model.TenderStatusPublished && !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionView) {
+		return nil, ErrNotEnoughPerm
+	}
+
+	return s.hub.Watch(ctx, tenderId, orgId), nil
+
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 	"zadanie/model"
 
 	"github.com/gofrs/uuid"
@@ -11,8 +12,15 @@ import (
 )
 
 func (s *Storage) tender(ctx context.Context, id uuid.UUID) (model.Tender, error) {
+	return s.findTender(ctx, `SELECT * FROM tender WHERE id = $1;`, id)
+}
+
+func (s *Storage) lockTender(ctx context.Context, id uuid.UUID) (model.Tender, error) {
+	return s.findTender(ctx, `SELECT * FROM tender WHERE id = $1 FOR UPDATE;`, id)
+}
+
+func (s *Storage) findTender(ctx context.Context, query string, id uuid.UUID) (model.Tender, error) {
 
-	query := `SELECT * FROM tender WHERE id = $1`
 	row, err := s.conn.Query(ctx, query, id)
 	if err != nil {
 		return model.Tender{}, err
@@ -51,131 +59,154 @@ func (s *Storage) CreateTender(ctx context.Context, tender model.Tender, usernam
 		return model.Tender{}, err
 	}
 
-	if !s.checkRelationToOrganization(ctx, userId, tender.OrganizationId) {
+	if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionCreateTender) {
 		return model.Tender{}, ErrNotEnoughPerm
 	}
 
-	insert := `	INSERT INTO tender(name, description, type, status, organization_id)
-				VALUES ($1, $2, $3, $4, $5)
-				RETURNING *;`
+	if !model.ValidateDeadlines(tender, tender, time.Now()) {
+		return model.Tender{}, ErrIncorrectDeadline
+	}
 
-	row, err := s.conn.Query(ctx, insert, tender.Name, tender.Description, tender.ServiceType, model.TenderStatusCreated, tender.OrganizationId)
-	if err != nil {
-		return model.Tender{}, err
+	if !tender.ValidateBudget() {
+		return model.Tender{}, ErrIncorrectBudget
+	}
+
+	if !tender.ValidatePolicy() {
+		return model.Tender{}, ErrIncorrectPolicy
 	}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Tender])
+	if len(tender.BudgetPolicy) == 0 {
+		tender.BudgetPolicy = model.BudgetPolicyWarn
+	}
+
+	if len(tender.ApprovalRule) == 0 {
+		tender.ApprovalRule = model.ApprovalRuleQuorum
+	}
+
+	if len(tender.RejectionRule) == 0 {
+		tender.RejectionRule = model.RejectionRuleFirstVeto
+	}
+
+	insert := `	INSERT INTO tender(name, description, type, status, organization_id, submission_deadline, decision_deadline,
+					budget_min, budget_max, currency, budget_policy, approval_rule, approval_threshold, rejection_rule, max_awards, modified_by, operation)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+				RETURNING *;`
+
+	args := []any{tender.Name, tender.Description, tender.ServiceType, model.TenderStatusCreated, tender.OrganizationId,
+		utc(tender.SubmissionDeadline), utc(tender.DecisionDeadline), tender.BudgetMin, tender.BudgetMax, tender.Currency, tender.BudgetPolicy,
+		tender.ApprovalRule, tender.ApprovalThreshold, tender.RejectionRule, tender.MaxAwards, userId, model.OperationCreate}
+
+	return mutate(ctx, s, insert, args, func(created model.Tender) model.AuditEvent {
+		return model.TenderEvent(model.AuditTenderCreate, &userId, nil, created)
+	})
 
 }
 
-func (s *Storage) ReadTenders(ctx context.Context, limit, offset int, types []model.TenderServiceType) ([]model.Tender, error) {
+func (s *Storage) ReadTenders(ctx context.Context, opts model.ListOptions, types []model.TenderServiceType) (model.Page[model.Tender], error) {
 
-	query := `	SELECT * FROM tender WHERE status = 'Published' `
+	q := query{}
+	from := `FROM tender WHERE status = 'Published' `
 
 	if len(types) != 0 {
+		from += "AND " + in(&q, "type", types) + " "
+	}
 
-		parts := []string{}
+	return readPage[model.Tender](ctx, s, "tender", from, q, opts)
 
-		for _, t := range types {
-			parts = append(parts, fmt.Sprintf("type = '%s' ", string(t)))
-		}
+}
 
-		query += fmt.Sprintf("AND ( %s ) ", strings.Join(parts, "OR "))
-	}
+func (s *Storage) SearchTenders(ctx context.Context, search string, opts model.ListOptions, types []model.TenderServiceType) (model.Page[model.TenderMatch], error) {
 
-	query += `	ORDER BY name ASC LIMIT $1 OFFSET $2 ;`
+	q := query{}
+	tsquery := q.arg(search)
+	from := fmt.Sprintf(`	FROM (
+					SELECT tender.*,
+						ts_rank(tender_document(name, description), websearch_to_tsquery('russian', %[1]s)) AS rank,
+						ts_headline('russian', name || ' — ' || COALESCE(description, ''), websearch_to_tsquery('russian', %[1]s),
+							'StartSel=<b>, StopSel=</b>, MaxFragments=2, MinWords=5, MaxWords=20') AS snippet
+					FROM tender
+					WHERE status = 'Published'
+					AND tender_document(name, description) @@ websearch_to_tsquery('russian', %[1]s) `, tsquery)
 
-	row, err := s.conn.Query(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
+	if len(types) != 0 {
+		from += "AND " + in(&q, "type", types) + " "
 	}
 
-	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Tender])
+	from += `) AS match WHERE TRUE`
+
+	return readPage[model.TenderMatch](ctx, s, "match", from, q, opts)
 
 }
 
-func (s *Storage) ReadMyTenders(ctx context.Context, username string, limit, offset int) ([]model.Tender, error) {
+func (s *Storage) ReadMyTenders(ctx context.Context, username string, opts model.ListOptions) (model.Page[model.Tender], error) {
 
 	userId, err := s.userId(ctx, username)
 	if err != nil {
-		return nil, err
+		return model.Page[model.Tender]{}, err
 	}
 
 	orgId, err := s.userOrgId(ctx, userId)
 	if err != nil {
-		return nil, err
+		return model.Page[model.Tender]{}, err
 	}
 
-	row, err := s.conn.Query(ctx, `SELECT * FROM tender WHERE organization_id = $1 ORDER BY name ASC LIMIT $2 OFFSET $3;`, orgId, limit, offset)
-	if err != nil {
-		return nil, err
-	}
+	q := query{}
+	from := `FROM tender WHERE organization_id = ` + q.arg(orgId)
 
-	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Tender])
+	return readPage[model.Tender](ctx, s, "tender", from, q, opts)
 
 }
 
-func (s *Storage) ReadTenderStatus(ctx context.Context, tenderId uuid.UUID, username string) (model.TenderStatus, error) {
+func (s *Storage) ReadTender(ctx context.Context, tenderId uuid.UUID, username string) (model.Tender, error) {
 
 	tender, err := s.tender(ctx, tenderId)
 	if err != nil {
-		return "", err
+		return model.Tender{}, err
 	}
 
 	if tender.Status == model.TenderStatusPublished {
-		return tender.Status, nil
+		return tender, nil
 	}
 
 	userId, err := s.userId(ctx, username)
 	if err != nil {
-		return "", err
+		return model.Tender{}, err
 	}
 
-	if !s.checkRelationToOrganization(ctx, userId, tender.OrganizationId) {
-		return "", ErrNotEnoughPerm
+	if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionView) {
+		return model.Tender{}, ErrNotEnoughPerm
 	}
 
-	return tender.Status, nil
+	return tender, nil
 
 }
 
-func (s *Storage) UpdateTenderStatus(ctx context.Context, tenderId uuid.UUID, username string, status model.TenderStatus) (model.Tender, error) {
+func (s *Storage) ReadTenderVersions(ctx context.Context, tenderId uuid.UUID, username string) ([]model.Tender, error) {
 
 	tender, err := s.tender(ctx, tenderId)
 	if err != nil {
-		return model.Tender{}, err
+		return nil, err
 	}
 
 	userId, err := s.userId(ctx, username)
 	if err != nil {
-		return model.Tender{}, err
-	}
-
-	if !s.checkRelationToOrganization(ctx, userId, tender.OrganizationId) {
-		return model.Tender{}, ErrNotEnoughPerm
+		return nil, err
 	}
 
-	if tender.Status == model.TenderStatusClosed {
-		return model.Tender{}, ErrTenderClosed
+	if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionView) {
+		return nil, ErrNotEnoughPerm
 	}
 
-	update := `	UPDATE tender 
-				SET status = $1, 
-					version = version + 1, 
-					updated_at = now()::timestamp without time zone
-				WHERE id = $2 
-				RETURNING *;`
-
-	row, err := s.conn.Query(ctx, update, status, tenderId)
+	row, err := s.conn.Query(ctx, `SELECT * FROM tender_archive WHERE id = $1 ORDER BY version ASC;`, tenderId)
 	if err != nil {
-		return model.Tender{}, err
+		return nil, err
 	}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Tender])
+	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Tender])
 
 }
 
-func (s *Storage) UpdateTender(ctx context.Context, tenderId uuid.UUID, username string, new model.Tender) (model.Tender, error) {
+func (s *Storage) ReadTenderVersion(ctx context.Context, tenderId uuid.UUID, username string, ver int) (model.Tender, error) {
 
 	tender, err := s.tender(ctx, tenderId)
 	if err != nil {
@@ -187,74 +218,298 @@ func (s *Storage) UpdateTender(ctx context.Context, tenderId uuid.UUID, username
 		return model.Tender{}, err
 	}
 
-	if !s.checkRelationToOrganization(ctx, userId, tender.OrganizationId) {
+	if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionView) {
 		return model.Tender{}, ErrNotEnoughPerm
 	}
 
-	if tender.Status == model.TenderStatusClosed {
-		return model.Tender{}, ErrTenderClosed
-	}
+	return s.tenderVer(ctx, tenderId, ver)
 
-	parts := []string{}
-	if len(new.Name) != 0 {
-		parts = append(parts, fmt.Sprintf("name = '%s' ", new.Name))
-	}
-	if len(new.Description) != 0 {
-		parts = append(parts, fmt.Sprintf("description = '%s' ", new.Description))
-	}
-	if len(new.ServiceType) != 0 {
-		parts = append(parts, fmt.Sprintf("type = '%s' ", new.ServiceType))
-	}
-	parts = append(parts, "version = version + 1 ")
-	parts = append(parts, "updated_at = now()::timestamp without time zone ")
+}
 
-	update := fmt.Sprintf(`UPDATE tender SET %s WHERE id = $1 RETURNING *;`, strings.Join(parts, ","))
+func (s *Storage) UpdateTenderStatus(ctx context.Context, tenderId uuid.UUID, username string, status model.TenderStatus, match model.Versions) (model.Tender, error) {
+	return atomic(ctx, s, func(s *Storage) (model.Tender, error) {
 
-	row, err := s.conn.Query(ctx, update, tenderId)
-	if err != nil {
-		return model.Tender{}, err
-	}
+		tender, err := s.lockTender(ctx, tenderId)
+		if err != nil {
+			return model.Tender{}, err
+		}
+
+		userId, err := s.userId(ctx, username)
+		if err != nil {
+			return model.Tender{}, err
+		}
+
+		if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionPublishTender) {
+			return model.Tender{}, ErrNotEnoughPerm
+		}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Tender])
+		if !match.Match(int(tender.Version)) {
+			return model.Tender{}, ErrVersionMismatch
+		}
+
+		if tender.Status == model.TenderStatusClosed {
+			return model.Tender{}, ErrTenderClosed
+		}
+
+		update := `	UPDATE tender 
+					SET status = $1, 
+						close_reason = NULL,
+						modified_by = $2,
+						operation = $3,
+						version = version + 1, 
+						updated_at = now()::timestamp without time zone
+					WHERE id = $4 
+					RETURNING *;`
+
+		updated, err := mutate(ctx, s, update, []any{status, userId, model.OperationStatusChange, tenderId}, func(updated model.Tender) model.AuditEvent {
+			return model.TenderEvent(model.AuditTenderStatus, &userId, &tender, updated)
+		})
+		if err != nil {
+			return model.Tender{}, err
+		}
 
+		if updated.Status == model.TenderStatusClosed {
+			if err := s.rejectOpenBids(ctx, updated, &userId); err != nil {
+				return model.Tender{}, err
+			}
+		}
+
+		return updated, nil
+
+	})
 }
 
-func (s *Storage) RollbackTender(ctx context.Context, tenderId uuid.UUID, username string, ver int) (model.Tender, error) {
+func (s *Storage) UpdateTender(ctx context.Context, tenderId uuid.UUID, username string, new model.Tender, match model.Versions) (model.Tender, error) {
+	return atomic(ctx, s, func(s *Storage) (model.Tender, error) {
 
-	tender, err := s.tender(ctx, tenderId)
-	if err != nil {
-		return model.Tender{}, err
-	}
+		tender, err := s.lockTender(ctx, tenderId)
+		if err != nil {
+			return model.Tender{}, err
+		}
 
-	userId, err := s.userId(ctx, username)
-	if err != nil {
-		return model.Tender{}, err
-	}
+		userId, err := s.userId(ctx, username)
+		if err != nil {
+			return model.Tender{}, err
+		}
 
-	if !s.checkRelationToOrganization(ctx, userId, tender.OrganizationId) {
-		return model.Tender{}, ErrNotEnoughPerm
-	}
+		if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionEditTender) {
+			return model.Tender{}, ErrNotEnoughPerm
+		}
 
-	oldTender, err := s.tenderVer(ctx, tenderId, ver)
-	if err != nil {
-		return model.Tender{}, err
-	}
+		if !match.Match(int(tender.Version)) {
+			return model.Tender{}, ErrVersionMismatch
+		}
+
+		if tender.Status == model.TenderStatusClosed {
+			return model.Tender{}, ErrTenderClosed
+		}
+
+		q := query{}
+		parts := []string{}
+		if len(new.Name) != 0 {
+			parts = append(parts, q.set("name", new.Name))
+		}
+		if len(new.Description) != 0 {
+			parts = append(parts, q.set("description", new.Description))
+		}
+		if len(new.ServiceType) != 0 {
+			parts = append(parts, q.set("type", new.ServiceType))
+		}
+		if new.SubmissionDeadline != nil {
+			tender.SubmissionDeadline = new.SubmissionDeadline
+			parts = append(parts, q.set("submission_deadline", utc(new.SubmissionDeadline)))
+		}
+		if new.DecisionDeadline != nil {
+			tender.DecisionDeadline = new.DecisionDeadline
+			parts = append(parts, q.set("decision_deadline", utc(new.DecisionDeadline)))
+		}
 
-	query := `	UPDATE tender
-				SET name = $1,
-					description = $2,
-					type = $3,
-					status = $4,
-					version = version + 1, 
+		if new.BudgetMin != nil {
+			tender.BudgetMin = new.BudgetMin
+			parts = append(parts, q.set("budget_min", new.BudgetMin))
+		}
+		if new.BudgetMax != nil {
+			tender.BudgetMax = new.BudgetMax
+			parts = append(parts, q.set("budget_max", new.BudgetMax))
+		}
+		if new.Currency != nil {
+			tender.Currency = new.Currency
+			parts = append(parts, q.set("currency", new.Currency))
+		}
+		if len(new.BudgetPolicy) != 0 {
+			parts = append(parts, q.set("budget_policy", new.BudgetPolicy))
+		}
+
+		if !model.ValidateDeadlines(tender, new, time.Now()) {
+			return model.Tender{}, ErrIncorrectDeadline
+		}
+
+		if !tender.ValidateBudget() {
+			return model.Tender{}, ErrIncorrectBudget
+		}
+
+		if !tender.ValidatePolicy() {
+			return model.Tender{}, ErrIncorrectPolicy
+		}
+
+		parts = append(parts, q.set("modified_by", userId))
+		parts = append(parts, q.set("operation", model.OperationEdit))
+		parts = append(parts, "version = version + 1")
+		parts = append(parts, "updated_at = now()::timestamp without time zone")
+
+		update := fmt.Sprintf(`UPDATE tender SET %s WHERE id = %s RETURNING *;`, strings.Join(parts, ", "), q.arg(tenderId))
+
+		return mutate(ctx, s, update, q.args, func(updated model.Tender) model.AuditEvent {
+			return model.TenderEvent(model.AuditTenderEdit, &userId, &tender, updated)
+		})
+
+	})
+}
+
+func (s *Storage) RollbackTender(ctx context.Context, tenderId uuid.UUID, username string, ver int, match model.Versions) (model.Tender, error) {
+	return atomic(ctx, s, func(s *Storage) (model.Tender, error) {
+
+		tender, err := s.lockTender(ctx, tenderId)
+		if err != nil {
+			return model.Tender{}, err
+		}
+
+		userId, err := s.userId(ctx, username)
+		if err != nil {
+			return model.Tender{}, err
+		}
+
+		if !s.checkPermission(ctx, userId, tender.OrganizationId, model.ActionEditTender) {
+			return model.Tender{}, ErrNotEnoughPerm
+		}
+
+		if !match.Match(int(tender.Version)) {
+			return model.Tender{}, ErrVersionMismatch
+		}
+
+		oldTender, err := s.tenderVer(ctx, tenderId, ver)
+		if err != nil {
+			return model.Tender{}, err
+		}
+
+		query := `	UPDATE tender
+					SET name = $1,
+						description = $2,
+						type = $3,
+						status = $4,
+						submission_deadline = $5,
+						decision_deadline = $6,
+						close_reason = $7,
+						budget_min = $8,
+						budget_max = $9,
+						currency = $10,
+						budget_policy = $11,
+						modified_by = $12,
+						operation = $13,
+						version = version + 1, 
+						updated_at = now()::timestamp without time zone
+					WHERE id = $14
+					RETURNING *;`
+
+		args := []any{oldTender.Name, oldTender.Description, oldTender.ServiceType, oldTender.Status,
+			oldTender.SubmissionDeadline, oldTender.DecisionDeadline, oldTender.CloseReason,
+			oldTender.BudgetMin, oldTender.BudgetMax, oldTender.Currency, oldTender.BudgetPolicy, userId, model.OperationRollback, tenderId}
+
+		updated, err := mutate(ctx, s, query, args, func(updated model.Tender) model.AuditEvent {
+			return model.TenderEvent(model.AuditTenderRollback, &userId, &tender, updated)
+		})
+		if err != nil {
+			return model.Tender{}, err
+		}
+
+		if updated.Status == model.TenderStatusClosed && tender.Status != model.TenderStatusClosed {
+			if err := s.rejectOpenBids(ctx, updated, &userId); err != nil {
+				return model.Tender{}, err
+			}
+		}
+
+		return updated, nil
+
+	})
+}
+
+func (s *Storage) CloseExpiredTenders(ctx context.Context, now time.Time) ([]model.Tender, error) {
+
+	lock := `	SELECT id
+				FROM tender
+				WHERE status = $1
+				AND decision_deadline <= $2
+				ORDER BY id
+				FOR UPDATE;`
+
+	update := `	UPDATE tender
+				SET status = $1,
+					close_reason = $2,
+					modified_by = NULL,
+					operation = $3,
+					version = version + 1,
 					updated_at = now()::timestamp without time zone
-				WHERE id = $5
+				WHERE id = ANY($4)
 				RETURNING *;`
 
-	row, err := s.conn.Query(ctx, query, oldTender.Name, oldTender.Description, oldTender.ServiceType, oldTender.Status, tenderId)
-	if err != nil {
-		return model.Tender{}, err
+	return atomic(ctx, s, func(s *Storage) ([]model.Tender, error) {
+
+		row, err := s.conn.Query(ctx, lock, model.TenderStatusPublished, now.UTC())
+		if err != nil {
+			return nil, err
+		}
+
+		ids, err := pgx.CollectRows(row, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ids) == 0 {
+			return nil, nil
+		}
+
+		row, err = s.conn.Query(ctx, update, model.TenderStatusClosed, model.TenderCloseReasonDecisionDeadline,
+			model.OperationClose, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		closed, err := pgx.CollectRows(row, pgx.RowToStructByNameLax[model.Tender])
+		if err != nil {
+			return nil, err
+		}
+
+		events := make([]model.AuditEvent, 0, len(closed))
+		for _, tender := range closed {
+			before := tender
+			before.Version--
+			events = append(events, model.TenderEvent(model.AuditTenderClose, nil, &before, tender))
+		}
+
+		if err := audit(ctx, s.conn, events...); err != nil {
+			return nil, err
+		}
+
+		for _, tender := range closed {
+			if err := s.rejectOpenBids(ctx, tender, nil); err != nil {
+				return nil, err
+			}
+		}
+
+		return closed, nil
+
+	})
+
+}
+
+func utc(t *time.Time) *time.Time {
+
+	if t == nil {
+		return nil
 	}
 
-	return pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.Tender])
+	u := t.UTC()
+
+	return &u
 
 }
@@ -0,0 +1,110 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+)
+
+var hostile = []string{
+	`O'Brien`,
+	`'; DROP TABLE tender; --`,
+	`%_\`,
+	`" OR 1=1 --`,
+	`$1`,
+	`Доставка «срочно» 🚚`,
+	"\u200b\ufeff\u202e",
+}
+
+func TestHostileValuesRoundTrip(t *testing.T) {
+
+	s, pool := newTestStorage(t)
+	ctx := context.Background()
+
+	prefix := uuid.Must(uuid.NewV4()).String()[:8]
+	owner, author := prefix+"-owner", prefix+"-author"
+	org := addOrganization(t, pool, prefix+" org", addEmployee(t, pool, owner))
+	authorId := addEmployee(t, pool, author)
+	addOrganization(t, pool, prefix+" author org", authorId)
+
+	opts := model.ListOptions{Sort: "-updated_at", Limit: 50}
+
+	for _, value := range hostile {
+		t.Run(value, func(t *testing.T) {
+
+			tender, err := s.CreateTender(ctx, model.Tender{
+				Name:           "t",
+				Description:    "d",
+				ServiceType:    model.TenderServiceTypeDelivery,
+				OrganizationId: org,
+			}, owner)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.UpdateTenderStatus(ctx, tender.Id, owner, model.TenderStatusPublished, nil); err != nil {
+				t.Fatal(err)
+			}
+			if tender, err = s.UpdateTender(ctx, tender.Id, owner, model.Tender{Name: value, Description: value}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if tender.Name != value || tender.Description != value {
+				t.Fatalf("updated tender %q, %q", tender.Name, tender.Description)
+			}
+
+			page, err := s.ReadTenders(ctx, opts, []model.TenderServiceType{model.TenderServiceTypeDelivery})
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, got := range page.Items {
+				if got.Id == tender.Id {
+					found = got.Name == value && got.Description == value
+				}
+			}
+			if !found {
+				t.Fatalf("tender %q not read back unchanged", value)
+			}
+
+			// Postgres rejects a value outside the service_type enum; either way nothing matches.
+			page, err = s.ReadTenders(ctx, opts, []model.TenderServiceType{model.TenderServiceType(value)})
+			if err == nil && len(page.Items) != 0 {
+				t.Fatalf("service type %q matched %d tenders", value, len(page.Items))
+			}
+
+			bid, err := s.CreateBid(ctx, model.Bid{
+				Name:        "b",
+				Description: "d",
+				TenderId:    tender.Id,
+				AuthorType:  model.BidAuthorTypeUser,
+				AuthorId:    authorId,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bid, err = s.UpdateBid(ctx, bid.Id, author, model.Bid{Name: value, Description: value}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if bid.Name != value || bid.Description != value {
+				t.Fatalf("updated bid %q, %q", bid.Name, bid.Description)
+			}
+
+			bids, err := s.ReadMyBids(ctx, author, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found = false
+			for _, got := range bids.Items {
+				if got.Id == bid.Id {
+					found = got.Name == value && got.Description == value
+				}
+			}
+			if !found {
+				t.Fatalf("bid %q not read back unchanged", value)
+			}
+
+		})
+	}
+
+}
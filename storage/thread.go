@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"time"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type participant struct {
+	userId uuid.UUID
+	orgId  uuid.UUID
+	side   model.FeedbackSide
+	bid    model.Bid
+}
+
+func (s *Storage) participant(ctx context.Context, bidId uuid.UUID, username string, post bool) (participant, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return participant{}, err
+	}
+
+	bid, err := s.bid(ctx, bidId)
+	if err != nil {
+		return participant{}, err
+	}
+
+	tender, err := s.tender(ctx, bid.TenderId)
+	if err != nil {
+		return participant{}, err
+	}
+
+	bidOrgId, err := s.userOrgId(ctx, bid.AuthorId)
+	hasOrg := err == nil
+
+	authorAction, reviewerAction := model.ActionView, model.ActionView
+	if post {
+		authorAction, reviewerAction = model.ActionEditBid, model.ActionFeedback
+	}
+
+	p := participant{userId: userId, bid: bid}
+	switch {
+	case userId == bid.AuthorId, hasOrg && s.checkPermission(ctx, userId, bidOrgId, authorAction):
+		p.side, p.orgId = model.FeedbackSideAuthor, bidOrgId
+	case bid.Status == model.BidStatusCreated || bid.Status == model.BidStatusCanceled:
+		return participant{}, ErrNotEnoughPerm
+	case s.checkPermission(ctx, userId, tender.OrganizationId, reviewerAction):
+		p.side, p.orgId = model.FeedbackSideReviewer, tender.OrganizationId
+	default:
+		return participant{}, ErrNotEnoughPerm
+	}
+
+	return p, nil
+
+}
+
+func (s *Storage) message(ctx context.Context, p participant, messageId uuid.UUID) (model.BidFeedback, error) {
+
+	query := `SELECT * FROM bid_feedback WHERE id = $1 AND bid_id = $2 FOR UPDATE;`
+	row, err := s.conn.Query(ctx, query, messageId, p.bid.Id)
+	if err != nil {
+		return model.BidFeedback{}, err
+	}
+
+	message, err := pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.BidFeedback])
+	if err != nil || !message.VisibleTo(p.side) {
+		return model.BidFeedback{}, ErrMessageNotFound
+	}
+
+	return message, nil
+
+}
+
+func (s *Storage) ownMessage(ctx context.Context, p participant, messageId uuid.UUID) (model.BidFeedback, error) {
+
+	message, err := s.message(ctx, p, messageId)
+	if err != nil {
+		return model.BidFeedback{}, err
+	}
+
+	if message.UserId == nil || *message.UserId != p.userId {
+		return model.BidFeedback{}, ErrNotEnoughPerm
+	}
+
+	if !message.Editable(time.Now().UTC()) {
+		return model.BidFeedback{}, ErrMessageLocked
+	}
+
+	return message, nil
+
+}
+
+func (s *Storage) ReadBidThread(ctx context.Context, bidId uuid.UUID, username string, opts model.ListOptions) (model.Page[model.BidFeedback], error) {
+
+	p, err := s.participant(ctx, bidId, username, false)
+	if err != nil {
+		return model.Page[model.BidFeedback]{}, err
+	}
+
+	q := query{}
+	from := `FROM bid_feedback WHERE ` + q.set("bid_id", bidId) +
+		` AND (visibility = 'Public' OR ` + q.set("side", p.side) + `)`
+
+	return readPage[model.BidFeedback](ctx, s, "bid_feedback", from, q, opts)
+
+}
+
+func (s *Storage) PostBidMessage(ctx context.Context, bidId uuid.UUID, username string, message model.BidFeedback) (model.BidFeedback, error) {
+	return atomic(ctx, s, func(s *Storage) (model.BidFeedback, error) {
+
+		p, err := s.participant(ctx, bidId, username, true)
+		if err != nil {
+			return model.BidFeedback{}, err
+		}
+
+		if message.ParentId != nil {
+			if _, err := s.message(ctx, p, *message.ParentId); err != nil {
+				return model.BidFeedback{}, err
+			}
+		}
+
+		if len(message.Visibility) == 0 {
+			message.Visibility = model.FeedbackVisibilityPublic
+		}
+
+		insert := `	INSERT INTO bid_feedback(bid_id, tender_id, user_id, description, parent_id, side, visibility)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)
+					RETURNING *;`
+
+		args := []any{bidId, p.bid.TenderId, p.userId, message.Description, message.ParentId, p.side, message.Visibility}
+
+		return mutate(ctx, s, insert, args, func(posted model.BidFeedback) model.AuditEvent {
+			return model.BidEvent(model.AuditBidMessage, &p.userId, p.orgId, &p.bid, p.bid).About(posted)
+		})
+
+	})
+}
+
+func (s *Storage) EditBidMessage(ctx context.Context, bidId, messageId uuid.UUID, username string, text string) (model.BidFeedback, error) {
+	return atomic(ctx, s, func(s *Storage) (model.BidFeedback, error) {
+
+		p, err := s.participant(ctx, bidId, username, true)
+		if err != nil {
+			return model.BidFeedback{}, err
+		}
+
+		if _, err := s.ownMessage(ctx, p, messageId); err != nil {
+			return model.BidFeedback{}, err
+		}
+
+		update := `	UPDATE bid_feedback
+					SET description = $1,
+						updated_at = now()::timestamp without time zone
+					WHERE id = $2
+					RETURNING *;`
+
+		return mutate(ctx, s, update, []any{text, messageId}, func(model.BidFeedback) model.AuditEvent {
+			return model.BidEvent(model.AuditBidMessageEdit, &p.userId, p.orgId, &p.bid, p.bid)
+		})
+
+	})
+}
+
+func (s *Storage) DeleteBidMessage(ctx context.Context, bidId, messageId uuid.UUID, username string) error {
+
+	_, err := atomic(ctx, s, func(s *Storage) (model.BidFeedback, error) {
+
+		p, err := s.participant(ctx, bidId, username, true)
+		if err != nil {
+			return model.BidFeedback{}, err
+		}
+
+		if _, err := s.ownMessage(ctx, p, messageId); err != nil {
+			return model.BidFeedback{}, err
+		}
+
+		update := `	UPDATE bid_feedback
+					SET description = '',
+						deleted_at = now()::timestamp without time zone
+					WHERE id = $1
+					RETURNING *;`
+
+		return mutate(ctx, s, update, []any{messageId}, func(model.BidFeedback) model.AuditEvent {
+			return model.BidEvent(model.AuditBidMessageDrop, &p.userId, p.orgId, &p.bid, p.bid)
+		})
+
+	})
+
+	return err
+
+}
@@ -0,0 +1,316 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"zadanie/dto"
+	"zadanie/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func enqueueWebhooks(ctx context.Context, db conn, e model.AuditEvent) error {
+
+	eventType, ok := e.WebhookEvent()
+	if !ok {
+		return nil
+	}
+
+	orgIds := []uuid.UUID{e.OrganizationId}
+	if e.EntityType == model.EntityBid {
+
+		var tenderOrgId uuid.UUID
+		var authorOrgId *uuid.UUID
+		var status model.BidStatus
+
+		query := `	SELECT t.organization_id, b.status, (
+						SELECT organization_id
+						FROM organization_responsible
+						WHERE user_id = b.author_id
+						LIMIT 1)
+					FROM bid b
+					JOIN tender t ON t.id = b.tender_id
+					WHERE b.id = $1;`
+
+		if err := db.QueryRow(ctx, query, e.EntityId).Scan(&tenderOrgId, &status, &authorOrgId); err != nil {
+			return err
+		}
+
+		orgIds = []uuid.UUID{}
+		if status != model.BidStatusCreated {
+			orgIds = append(orgIds, tenderOrgId)
+		}
+		if authorOrgId != nil {
+			orgIds = append(orgIds, *authorOrgId)
+		}
+	}
+
+	payload, err := json.Marshal(dto.NewWebhookEvent(e, eventType))
+	if err != nil {
+		return err
+	}
+
+	insert := `	INSERT INTO webhook_delivery(endpoint_id, organization_id, event_id, event_type, payload)
+				SELECT id, organization_id, $1, $2, $3
+				FROM webhook_endpoint
+				WHERE enabled
+				AND $2 = ANY(event_types)
+				AND organization_id = ANY($4);`
+
+	_, err = db.Exec(ctx, insert, e.Id, eventType, payload, orgIds)
+	return err
+
+}
+
+const webhookColumns = `id, organization_id, url, secret, event_types, enabled, created_by, created_at`
+
+func scanWebhook(row pgx.CollectableRow) (model.WebhookEndpoint, error) {
+
+	endpoint := model.WebhookEndpoint{}
+	eventTypes := []string{}
+	if err := row.Scan(&endpoint.Id, &endpoint.OrganizationId, &endpoint.URL, &endpoint.Secret, &eventTypes,
+		&endpoint.Enabled, &endpoint.CreatedBy, &endpoint.CreatedAt); err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+
+	endpoint.EventTypes = make([]model.WebhookEventType, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		endpoint.EventTypes = append(endpoint.EventTypes, model.WebhookEventType(t))
+	}
+
+	return endpoint, nil
+
+}
+
+func (s *Storage) hookOrgId(ctx context.Context, username string) (uuid.UUID, uuid.UUID, error) {
+
+	userId, err := s.userId(ctx, username)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+
+	orgId, err := s.userOrgId(ctx, userId)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+
+	if !s.checkPermission(ctx, userId, orgId, model.ActionManageHooks) {
+		return uuid.UUID{}, uuid.UUID{}, ErrNotEnoughPerm
+	}
+
+	return userId, orgId, nil
+
+}
+
+func (s *Storage) CreateWebhook(ctx context.Context, username string, endpoint model.WebhookEndpoint) (model.WebhookEndpoint, error) {
+
+	userId, orgId, err := s.hookOrgId(ctx, username)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+
+	eventTypes := make([]string, 0, len(endpoint.EventTypes))
+	for _, t := range endpoint.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+
+	enabled := endpoint.Enabled == nil || *endpoint.Enabled
+
+	insert := `	INSERT INTO webhook_endpoint(organization_id, url, secret, event_types, enabled, created_by)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING ` + webhookColumns + `;`
+
+	row, err := s.conn.Query(ctx, insert, orgId, endpoint.URL, model.NewWebhookSecret(), eventTypes, enabled, userId)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+
+	return pgx.CollectOneRow(row, scanWebhook)
+
+}
+
+func (s *Storage) ReadWebhooks(ctx context.Context, username string) ([]model.WebhookEndpoint, error) {
+
+	_, orgId, err := s.hookOrgId(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + webhookColumns + ` FROM webhook_endpoint WHERE organization_id = $1 ORDER BY created_at ASC, id ASC;`
+
+	row, err := s.conn.Query(ctx, query, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(row, scanWebhook)
+
+}
+
+func (s *Storage) DeleteWebhook(ctx context.Context, username string, webhookId uuid.UUID) error {
+
+	_, orgId, err := s.hookOrgId(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.conn.Exec(ctx, `DELETE FROM webhook_endpoint WHERE id = $1 AND organization_id = $2;`, webhookId, orgId)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+
+}
+
+func (s *Storage) ReadDeliveries(ctx context.Context, username string, filter model.DeliveryFilter, opts model.ListOptions) (model.Page[model.WebhookDelivery], error) {
+
+	_, orgId, err := s.hookOrgId(ctx, username)
+	if err != nil {
+		return model.Page[model.WebhookDelivery]{}, err
+	}
+
+	exists := 0
+	owned := `SELECT 1 FROM webhook_endpoint WHERE id = $1 AND organization_id = $2;`
+	if err := s.conn.QueryRow(ctx, owned, filter.EndpointId, orgId).Scan(&exists); err != nil {
+		return model.Page[model.WebhookDelivery]{}, ErrWebhookNotFound
+	}
+
+	table := "webhook_delivery"
+	if filter.Status == model.DeliveryStatusDead {
+		table = "webhook_dead_letter"
+	}
+
+	q := query{}
+	from := `FROM ` + table + ` WHERE ` + q.set("endpoint_id", filter.EndpointId)
+	if filter.Status == model.DeliveryStatusPending || filter.Status == model.DeliveryStatusDelivered {
+		from += ` AND ` + q.set("status", filter.Status)
+	}
+
+	return readPage[model.WebhookDelivery](ctx, s, table, from, q, opts)
+
+}
+
+func (s *Storage) Redeliver(ctx context.Context, username string, deliveryId uuid.UUID) (model.WebhookDelivery, error) {
+
+	_, orgId, err := s.hookOrgId(ctx, username)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return atomic(ctx, s, func(s *Storage) (model.WebhookDelivery, error) {
+
+		revive := `	WITH dead AS (
+						DELETE FROM webhook_dead_letter
+						WHERE id = $1 AND organization_id = $2
+						RETURNING *)
+					INSERT INTO webhook_delivery(id, endpoint_id, organization_id, event_id, event_type, payload, created_at)
+					SELECT id, endpoint_id, organization_id, event_id, event_type, payload, created_at
+					FROM dead
+					RETURNING *;`
+
+		delivery, err := collectDelivery(s.conn.Query(ctx, revive, deliveryId, orgId))
+		if !errors.Is(err, ErrDeliveryNotFound) {
+			return delivery, err
+		}
+
+		retry := `	UPDATE webhook_delivery
+					SET status = 'Pending',
+						attempts = 0,
+						next_attempt_at = $3,
+						last_error = NULL,
+						response_code = NULL,
+						delivered_at = NULL
+					WHERE id = $1 AND organization_id = $2
+					RETURNING *;`
+
+		return collectDelivery(s.conn.Query(ctx, retry, deliveryId, orgId, time.Now().UTC()))
+
+	})
+
+}
+
+func collectDelivery(row pgx.Rows, err error) (model.WebhookDelivery, error) {
+
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	delivery, err := pgx.CollectOneRow(row, pgx.RowToStructByNameLax[model.WebhookDelivery])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+
+	return delivery, err
+
+}
+
+func (s *Storage) ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+
+	claim := `	UPDATE webhook_delivery d
+				SET next_attempt_at = $2
+				FROM webhook_endpoint e
+				WHERE e.id = d.endpoint_id
+				AND d.id IN (
+					SELECT id
+					FROM webhook_delivery
+					WHERE status = 'Pending'
+					AND next_attempt_at <= $1
+					ORDER BY next_attempt_at ASC
+					LIMIT $3
+					FOR UPDATE SKIP LOCKED)
+				RETURNING d.*, e.url, e.secret;`
+
+	row, err := s.conn.Query(ctx, claim, now.UTC(), now.Add(lease).UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(row, pgx.RowToStructByNameLax[model.WebhookDelivery])
+
+}
+
+func (s *Storage) SaveDelivery(ctx context.Context, d model.WebhookDelivery) error {
+
+	if d.Status == model.DeliveryStatusDead {
+
+		park := `	WITH gone AS (
+						DELETE FROM webhook_delivery
+						WHERE id = $1
+						RETURNING *)
+					INSERT INTO webhook_dead_letter(id, endpoint_id, organization_id, event_id, event_type, payload,
+						attempts, next_attempt_at, last_error, response_code, created_at)
+					SELECT id, endpoint_id, organization_id, event_id, event_type, payload, $2, $3, $4, $5, created_at
+					FROM gone;`
+
+		_, err := s.conn.Exec(ctx, park, d.Id, d.Attempts, d.NextAttemptAt.UTC(), d.LastError, d.ResponseCode)
+		return err
+
+	}
+
+	var deliveredAt *time.Time
+	if d.DeliveredAt != nil {
+		utc := d.DeliveredAt.UTC()
+		deliveredAt = &utc
+	}
+
+	update := `	UPDATE webhook_delivery
+				SET status = $2,
+					attempts = $3,
+					next_attempt_at = $4,
+					last_error = $5,
+					response_code = $6,
+					delivered_at = $7
+				WHERE id = $1;`
+
+	_, err := s.conn.Exec(ctx, update, d.Id, d.Status, d.Attempts, d.NextAttemptAt.UTC(), d.LastError, d.ResponseCode, deliveredAt)
+	return err
+
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"zadanie/auth"
+	"zadanie/config"
+)
+
+var errTokenUsage = errors.New("usage: token <username>")
+var errTokenSecret = errors.New("AUTH_SECRET is required to issue tokens")
+
+func token(ctx context.Context, args []string) error {
+
+	if len(args) != 1 {
+		return errTokenUsage
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Auth.Secret) == 0 {
+		return errTokenSecret
+	}
+
+	storage, err := newStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+
+	employee, err := storage.Employee(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	t, err := auth.NewIssuer(cfg.Auth.Secret, cfg.Auth.TokenTTL).Issue(employee)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(t)
+
+	return nil
+
+}
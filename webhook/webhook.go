@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+	"zadanie/config"
+	"zadanie/model"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+)
+
+var ErrForbiddenAddress = errors.New("webhook url points to a private or internal address")
+
+type Outbox interface {
+	ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	SaveDelivery(ctx context.Context, d model.WebhookDelivery) error
+}
+
+func Sign(secret string, timestamp int64, body []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+}
+
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, max)
+
+}
+
+func CheckURL(ctx context.Context, rawURL string) error {
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if !model.PublicAddr(addr) {
+			return ErrForbiddenAddress
+		}
+	}
+
+	return nil
+
+}
+
+func newClient(timeout time.Duration) *http.Client {
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !model.PublicAddr(addr.Addr()) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+
+}
+
+func Deliver(ctx context.Context, o Outbox, cfg config.Webhooks) {
+
+	client := newClient(cfg.Timeout)
+	lease := time.Duration(cfg.BatchSize+1) * cfg.Timeout
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		deliveries, err := o.ClaimDeliveries(ctx, time.Now(), int(cfg.BatchSize), lease)
+		if err != nil {
+			log.Printf("claim webhook deliveries: %s", err.Error())
+		}
+
+		for _, d := range deliveries {
+			if err := o.SaveDelivery(ctx, attempt(ctx, client, d, cfg)); err != nil {
+				log.Printf("save webhook delivery %s: %s", d.Id, err.Error())
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+}
+
+func attempt(ctx context.Context, client *http.Client, d model.WebhookDelivery, cfg config.Webhooks) model.WebhookDelivery {
+
+	d.Attempts++
+	code, err := send(ctx, client, d)
+	now := time.Now().UTC()
+
+	d.ResponseCode = nil
+	if code != 0 {
+		d.ResponseCode = &code
+	}
+
+	if err == nil {
+		d.Status, d.DeliveredAt, d.LastError = model.DeliveryStatusDelivered, &now, nil
+		return d
+	}
+
+	reason := err.Error()
+	d.LastError = &reason
+	d.NextAttemptAt = now.Add(Backoff(d.Attempts, cfg.BackoffBase, cfg.BackoffMax))
+
+	if d.Attempts >= int(cfg.MaxAttempts) {
+		d.Status = model.DeliveryStatusDead
+		log.Printf("webhook delivery %s parked after %d attempts: %s", d.Id, d.Attempts, reason)
+	}
+
+	return d
+
+}
+
+func send(ctx context.Context, client *http.Client, d model.WebhookDelivery) (int, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Payload))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderDelivery, d.Id.String())
+	req.Header.Set(HeaderEvent, string(d.EventType))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+
+}